@@ -0,0 +1,189 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/vicdeo/go-obfuscate/config"
+	"github.com/vicdeo/go-obfuscate/mysqldump"
+)
+
+const initConfigTemplate = `# See config.yaml.sample for all the options
+database:
+  driver: mysql
+  databaseName: "my_database"
+  net: tcp
+  hostname: "localhost"
+  port: "3306"
+  user: "user"
+  password: "secret"
+
+output:
+  fileNameFormat: "%s-2006-01-02T150405"
+  directory: "./dumps"
+
+tables:
+  keep:
+  ignore:
+  truncate:
+  obfuscate:
+`
+
+func runValidate(args []string) {
+	var configFilePath string
+	flags := newFlagSet("validate", &configFilePath)
+	flags.Parse(args)
+
+	loadConfig(configFilePath)
+	validateConfig()
+	fmt.Println("Config is valid")
+}
+
+func runCheck(args []string) {
+	var configFilePath string
+	flags := newFlagSet("check", &configFilePath)
+	flags.Parse(args)
+
+	loadConfig(configFilePath)
+	validateConfig()
+	db := connectDB()
+	defer db.Close()
+	checkDB(db)
+	fmt.Println("Config matches the database")
+}
+
+func runDump(args []string) {
+	var configFilePath string
+	flags := newFlagSet("dump", &configFilePath)
+	flags.Parse(args)
+
+	loadConfig(configFilePath)
+	validateConfig()
+	prepareFS()
+	db := connectDB()
+	checkDB(db)
+
+	// Register database with mysqldump
+	dumper, err := mysqldump.Register(db, conf)
+	exitOnError(err != nil, errDumpFileIsNotWritable, fmt.Sprintf("Error registering database: %v", err))
+
+	// TODO: add to config support of	dumper.LockTables = true
+	err = dumper.Dump()
+
+	// Close dumper, connected database and file stream.
+	dumper.Close()
+	exitOnError(err != nil, errDumpFailed, fmt.Sprintf("Error dumping: %v", err))
+
+	fmt.Printf("File is saved to %s\n", conf.GetDumpFileName())
+	if len(dumper.Excluded) > 0 {
+		fmt.Println("Excluded rows:")
+		tables := make([]string, 0, len(dumper.Excluded))
+		for name := range dumper.Excluded {
+			tables = append(tables, name)
+		}
+		sort.Strings(tables)
+		for _, name := range tables {
+			fmt.Printf(" - %s: %d\n", name, dumper.Excluded[name])
+		}
+	}
+	if len(dumper.Warnings) > 0 {
+		fmt.Println("Warnings:")
+		for _, warning := range dumper.Warnings {
+			fmt.Println(" -", warning)
+		}
+	}
+}
+
+func runInit(args []string) {
+	var configFilePath string
+	var force bool
+	flags := newFlagSet("init", &configFilePath)
+	flags.BoolVar(&force, "force", false, "Overwrite an existing config file")
+	flags.Parse(args)
+
+	if e, _ := exists(configFilePath); e && !force {
+		exitOnError(true, errConfigFileExists, fmt.Sprintf("Config file already exists: %s, use -force to overwrite it", configFilePath))
+	}
+	err := ioutil.WriteFile(configFilePath, []byte(initConfigTemplate), 0600)
+	exitOnError(err != nil, errConfigFileNotFound, fmt.Sprintf("Could not write config file: %v", err))
+	fmt.Println("Config file is saved to", configFilePath)
+}
+
+func runVerify(args []string) {
+	var dumpPath string
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	flags.StringVar(&dumpPath, "f", "", "Dump file or directory to verify")
+	flags.Parse(args)
+	exitOnError(dumpPath == "", errDumpVerificationFailed, "Please specify the dump with -f")
+
+	stats, err := mysqldump.Verify(dumpPath)
+	exitOnError(err != nil, errDumpVerificationFailed, fmt.Sprintf("Dump %s is broken: %v", dumpPath, err))
+	fmt.Printf("Dump %s is complete: %s format, %d tables, %d %s\n", dumpPath, stats.Format, stats.Tables, stats.Count, stats.CountOf)
+}
+
+func runRestore(args []string) {
+	var configFilePath, dumpPath, databaseName string
+	flags := newFlagSet("restore", &configFilePath)
+	flags.StringVar(&dumpPath, "f", "", "SQL dump file to restore")
+	flags.StringVar(&databaseName, "database", "", "Database to restore into, must differ from the configured source database")
+	flags.Parse(args)
+	exitOnError(dumpPath == "" || databaseName == "", errRestoreFailed, "Please specify the dump with -f and the target database with -database")
+
+	loadConfig(configFilePath)
+	exitOnError(databaseName == conf.Database.DatabaseName, errRestoreFailed, "Refusing to restore into the source database")
+
+	f, err := os.Open(dumpPath)
+	exitOnError(err != nil, errRestoreFailed, fmt.Sprintf("Could not open dump: %v", err))
+	defer f.Close()
+
+	source := conf.Database.DatabaseName
+	conf.Database.DatabaseName = databaseName
+	db := openDB(conf.Database.GetRestoreDSN())
+	resetPacket := func() {}
+	if conf.Database.GetDriver() != config.DriverPostgres {
+		db, resetPacket = raiseMaxAllowedPacket(db, f)
+	}
+	conf.Database.DatabaseName = source
+	defer db.Close()
+
+	count, err := mysqldump.Restore(db, f)
+	resetPacket()
+	var restoreErr *mysqldump.RestoreError
+	if errors.As(err, &restoreErr) {
+		exitOnError(true, errRestoreFailed, fmt.Sprintf("Error restoring line %d: %v", restoreErr.Line, restoreErr.Err))
+	}
+	exitOnError(err != nil, errRestoreFailed, fmt.Sprintf("Error restoring: %v", err))
+	fmt.Printf("%d statements are executed in %s\n", count, databaseName)
+}
+
+// raiseMaxAllowedPacket raises the global max_allowed_packet of the target server so the longest statement of the dump fits.
+// The session limit is copied from the global one on connect, so it reconnects and returns a func that restores the old limit
+func raiseMaxAllowedPacket(db *sql.DB, f *os.File) (*sql.DB, func()) {
+	size, err := mysqldump.LongestStatement(f)
+	exitOnError(err != nil, errRestoreFailed, fmt.Sprintf("Error reading dump: %v", err))
+	_, err = f.Seek(0, io.SeekStart)
+	exitOnError(err != nil, errRestoreFailed, fmt.Sprintf("Error reading dump: %v", err))
+
+	previous, err := mysqldump.RaiseMaxAllowedPacket(db, size)
+	exitOnError(err != nil, errRestoreFailed, fmt.Sprintf("Could not raise max_allowed_packet to fit a statement of %d bytes: %v", size, err))
+	if previous == 0 {
+		return db, func() {}
+	}
+	db.Close()
+	db = openDB(conf.Database.GetRestoreDSN())
+	return db, func() {
+		if err := mysqldump.SetMaxAllowedPacket(db, previous); err != nil {
+			fmt.Printf("Could not reset max_allowed_packet to %d: %v\n", previous, err)
+		}
+	}
+}
+
+func runVersion(args []string) {
+	fmt.Println("go-obfuscate version", version)
+}
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -14,6 +18,7 @@ import (
 type (
 	// DatabaseConfig -- Database connection config
 	DatabaseConfig struct {
+		Driver       string `yaml:"driver,omitempty"`
 		Net          string `yaml:"net,omitempty"`
 		Socket       string `yaml:"socket,omitempty"`
 		Hostname     string `yaml:"hostname,omitempty"`
@@ -21,12 +26,16 @@ type (
 		DatabaseName string `yaml:"databaseName,omitempty"`
 		User         string `yaml:"user,omitempty"`
 		Password     string `yaml:"password,omitempty"`
+		SSLMode      string `yaml:"sslMode,omitempty"`
 	}
 
 	// OutputConfig -- dump-specific options
 	OutputConfig struct {
-		FileNameFormat string `yaml:"fileNameFormat"`
-		Directory      string `yaml:"directory"`
+		FileNameFormat   string  `yaml:"fileNameFormat"`
+		Directory        string  `yaml:"directory"`
+		Format           string  `yaml:"format,omitempty"`
+		MaxAllowedPacket int     `yaml:"maxAllowedPacket,omitempty"`
+		NullString       *string `yaml:"nullString,omitempty"`
 	}
 
 	TableConfig struct {
@@ -34,6 +43,13 @@ type (
 		Ignore    []string               `yaml:"kept"`
 		Truncate  []string               `yaml:"kept"`
 		Obfuscate map[string]interface{} `yaml:"tables"`
+		Exclude   map[string]interface{} `yaml:"exclude"`
+	}
+
+	// ExcludeRule - rows of a table that never get into the dump, either an SQL predicate or a condition on the row values
+	ExcludeRule struct {
+		SQL       string
+		Condition *faker.Condition
 	}
 
 	// Config - global config
@@ -41,13 +57,40 @@ type (
 		Database *DatabaseConfig `yaml:"database"`
 		Output   *OutputConfig   `yaml:"output"`
 		Tables   *TableConfig    `yaml:"tables"`
-		clock    func() time.Time
+		// Locale of the names, phone numbers and addresses, a column can override it
+		Locale string `yaml:"locale,omitempty"`
+		clock  func() time.Time
+		// dir is the config file directory, relative file paths of the fakers start there
+		dir string
 	}
 )
 
 const (
 	ignoreMarker   = "ignore"
 	truncateMarker = "truncate"
+	domainMarker   = "domain"
+	fileMarker     = "file"
+	localeMarker   = "locale"
+	whenMarker     = "when"
+	unlessMarker   = "unless"
+	sqlMarker      = "sql"
+
+	// DriverMySQL - MySQL or MariaDB source database
+	DriverMySQL = "mysql"
+	// DriverPostgres - PostgreSQL source database
+	DriverPostgres = "postgres"
+
+	// FormatSQL - a single SQL dump file
+	FormatSQL = "sql"
+	// FormatCSV - a directory with a CSV file per table
+	FormatCSV = "csv"
+	// FormatNDJSON - a directory with a JSON Lines file per table
+	FormatNDJSON = "ndjson"
+	// FormatSQLite - a SQLite database file
+	FormatSQLite = "sqlite"
+
+	// DefaultNullString - marker of NULL in CSV files
+	DefaultNullString = `\N`
 )
 
 // Create a new Config instance.
@@ -71,7 +114,7 @@ func GetConf(configDir, configFileName string) (*Config, error) {
 		return nil, err
 	}
 
-	conf = &Config{}
+	conf = &Config{dir: configDir}
 	err = viper.Unmarshal(conf)
 	if err != nil {
 		return nil, err
@@ -93,20 +136,159 @@ func ShouldDumpData(tableName string) bool {
 	return !contains(conf.Tables.Truncate, tableName)
 }
 
-// GetColumnFaker - get a proper data generator
+// GetColumnFaker - get a proper data generator, nil if the column is not obfuscated or its config is invalid
 func GetColumnFaker(tableName, columnName string) faker.FakeGenerator {
-	defer func() {
-		if err := recover(); err != nil {
+	if _, ok := conf.obfuscatedColumn(tableName, columnName); !ok {
+		return nil
+	}
+	generator, err := conf.buildColumnFaker(tableName, columnName)
+	if err != nil {
+		return nil
+	}
+	return generator
+}
+
+// GetExcludeRule - rows of the table that are dropped, nil if all of them are dumped
+// An invalid rule is an error, the rows it was meant to drop must not get into the dump
+func GetExcludeRule(tableName string) (*ExcludeRule, error) {
+	return conf.excludeRule(tableName)
+}
+
+// excludeRule reads the exclude rule of the table, a condition or a map with an SQL predicate
+func (config *Config) excludeRule(tableName string) (*ExcludeRule, error) {
+	if config == nil || config.Tables == nil {
+		return nil, nil
+	}
+	value, ok := config.Tables.Exclude[tableName]
+	if !ok {
+		return nil, nil
+	}
+	switch rule := value.(type) {
+	case string:
+		condition, err := faker.ParseCondition(rule)
+		if err != nil {
+			return nil, fmt.Errorf("condition is invalid: %v", err)
 		}
-	}()
-	if table, ok := conf.Tables.Obfuscate[tableName]; ok {
-		tableMap := table.(map[string]interface{})
-		if column, ok := tableMap[columnName]; ok {
-			columnMap := column.(map[string]interface{})
-			return faker.New(columnMap)
+		return &ExcludeRule{Condition: condition}, nil
+	case map[string]interface{}:
+		predicate, ok := rule[sqlMarker].(string)
+		if !ok || strings.TrimSpace(predicate) == "" || len(rule) != 1 {
+			return nil, errors.New("rule must be a condition or an sql predicate")
+		}
+		return &ExcludeRule{SQL: predicate}, nil
+	}
+	return nil, errors.New("rule must be a condition or an sql predicate")
+}
+
+// obfuscatedColumn returns the config of the column listed in the obfuscate section
+func (config *Config) obfuscatedColumn(tableName, columnName string) (interface{}, bool) {
+	if config == nil || config.Tables == nil {
+		return nil, false
+	}
+	tableMap, ok := config.Tables.Obfuscate[tableName].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	column, ok := tableMap[columnName]
+	return column, ok
+}
+
+// buildColumnFaker creates the data generator of the column, the error tells what is wrong with the column config
+func (config *Config) buildColumnFaker(tableName, columnName string) (faker.FakeGenerator, error) {
+	column, _ := config.obfuscatedColumn(tableName, columnName)
+	columnMap, ok := column.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("type is missing")
+	}
+	generator, err := faker.Build(config.columnOptions(columnMap))
+	if err != nil {
+		return nil, err
+	}
+	// Columns of the same domain share fake values
+	if domain, ok := columnMap[domainMarker]; ok {
+		domainName, ok := domain.(string)
+		if !ok || domainName == "" {
+			return nil, errors.New("domain must be a name")
+		}
+		generator = faker.NewMappedGenerator(generator, faker.GetDomain(domainName))
+	}
+
+	when, err := columnCondition(columnMap, whenMarker)
+	if err != nil {
+		return nil, err
+	}
+	unless, err := columnCondition(columnMap, unlessMarker)
+	if err != nil {
+		return nil, err
+	}
+	return faker.NewConditionalGenerator(generator, when, unless), nil
+}
+
+// columnCondition parses the condition of the column rule, nil if it is not set
+func columnCondition(columnMap map[string]interface{}, marker string) (*faker.Condition, error) {
+	value, ok := columnMap[marker]
+	if !ok {
+		return nil, nil
+	}
+	expression, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an expression", marker)
+	}
+	condition, err := faker.ParseCondition(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%s is invalid: %v", marker, err)
+	}
+	return condition, nil
+}
+
+// columnOptions completes the column config with the global settings:
+// the relative file paths start at the config directory and the locale defaults to the global one
+func (config *Config) columnOptions(columnMap map[string]interface{}) map[string]interface{} {
+	resolved := config.resolveFiles(columnMap)
+	// An unknown global locale is reported once by ValidateObfuscateSection, not by every column
+	if _, ok := columnMap[localeMarker]; !ok && config.Locale != "" && faker.IsKnownLocale(config.Locale) {
+		resolved[localeMarker] = config.Locale
+	}
+	return resolved
+}
+
+// resolveFiles joins the relative file paths to the config directory, the ones of the nested options too, e.g. of JSON paths
+func (config *Config) resolveFiles(options map[string]interface{}) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(options)+1)
+	for k, v := range options {
+		switch value := v.(type) {
+		case string:
+			if k == fileMarker && value != "" && !filepath.IsAbs(value) && config.dir != "" {
+				v = filepath.Join(config.dir, value)
+			}
+		case []interface{}:
+			items := make([]interface{}, len(value))
+			for i, item := range value {
+				if itemMap, ok := optionMap(item); ok {
+					item = config.resolveFiles(itemMap)
+				}
+				items[i] = item
+			}
+			v = items
+		}
+		resolved[k] = v
+	}
+	return resolved
+}
+
+// optionMap reads the options of a list item, YAML gives them as a map of interface keys
+func optionMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			result[fmt.Sprintf("%v", k)] = v
 		}
+		return result, true
 	}
-	return nil
+	return nil, false
 }
 
 func (config *Config) ValidateConfig() (map[string][]string, bool) {
@@ -136,15 +318,46 @@ func (config *Config) ValidateConfig() (map[string][]string, bool) {
 	return messages, hasErrors
 }
 
+// ValidateObfuscateSection - check the generator options of the obfuscated columns
+// Each message holds table name, column name and the reason, warnings don't count as errors
+// The table name is empty for the global options
 func (config *Config) ValidateObfuscateSection() ([][]string, bool) {
 	hasErrors := false
 	messages := make([][]string, 0)
+	if config.Locale != "" && !faker.IsKnownLocale(config.Locale) {
+		messages = append(messages, []string{"", localeMarker, fmt.Sprintf("locale %s is not supported, use one of %s", config.Locale, strings.Join(faker.Locales(), ", "))})
+		hasErrors = true
+	}
 	for t, _ := range config.Tables.Obfuscate {
-		for c, _ := range config.Tables.Obfuscate[t].(map[string]interface{}) {
-			if GetColumnFaker(t, c) == nil {
-				messages = append(messages, []string{t, c})
+		columns, _ := config.Tables.Obfuscate[t].(map[string]interface{})
+		for c, _ := range columns {
+			generator, err := config.buildColumnFaker(t, c)
+			if err != nil {
+				messages = append(messages, []string{t, c, err.Error()})
 				hasErrors = true
+				continue
 			}
+			// Warnings are reported but don't stop the dump
+			for _, warning := range faker.Warnings(generator) {
+				messages = append(messages, []string{t, c, "warning: " + warning})
+			}
+		}
+	}
+	return messages, hasErrors
+}
+
+// ValidateExcludeSection - check the exclude rules, each message holds table name and the reason
+func (config *Config) ValidateExcludeSection() ([][]string, bool) {
+	hasErrors := false
+	messages := make([][]string, 0)
+	for t := range config.Tables.Exclude {
+		if _, err := config.excludeRule(t); err != nil {
+			messages = append(messages, []string{t, err.Error()})
+			hasErrors = true
+		} else if _, ok := config.Tables.Obfuscate[t]; !ok && !contains(config.Tables.Keep, t) {
+			// Every table of the database is listed, so a table that is neither kept nor obfuscated is not dumped or is a typo
+			messages = append(messages, []string{t, "the table is neither kept nor obfuscated"})
+			hasErrors = true
 		}
 	}
 	return messages, hasErrors
@@ -154,16 +367,88 @@ func (config *Config) GetDumpFullPath() string {
 	return path.Join(config.Output.Directory, config.GetDumpFileName())
 }
 
+// GetDumpFileName - name of the dump file, or of the dump directory for per-table formats
 func (config *Config) GetDumpFileName() string {
 	if dumpFileName == "" {
 		// Uses time.Time.Format (https://golang.org/pkg/time/#Time.Format). format appended with '.sql'.
 		dumpFileName = config.now().Format(config.Output.FileNameFormat)
-		dumpFileName = fmt.Sprintf(dumpFileName, config.Database.DatabaseName) + ".sql"
+		dumpFileName = fmt.Sprintf(dumpFileName, config.Database.DatabaseName)
+		switch config.Output.GetFormat() {
+		case FormatSQL:
+			dumpFileName += ".sql"
+		case FormatSQLite:
+			dumpFileName += ".sqlite"
+		}
 	}
 	return dumpFileName
 }
 
+// GetFormat - output format, SQL unless specified
+func (config *OutputConfig) GetFormat() string {
+	if config.Format == "" {
+		return FormatSQL
+	}
+	return strings.ToLower(config.Format)
+}
+
+// GetNullString - marker of NULL in CSV files, \N as LOAD DATA reads it unless specified
+func (config *OutputConfig) GetNullString() string {
+	if config.NullString == nil {
+		return DefaultNullString
+	}
+	return *config.NullString
+}
+
+// HasKnownFormat - check the output format is supported
+func (config *OutputConfig) HasKnownFormat() bool {
+	switch config.GetFormat() {
+	case FormatSQL, FormatCSV, FormatNDJSON, FormatSQLite:
+		return true
+	}
+	return false
+}
+
+// GetDriver - database/sql driver name, MySQL unless specified
+func (config *DatabaseConfig) GetDriver() string {
+	if config.Driver == "" {
+		return DriverMySQL
+	}
+	return strings.ToLower(config.Driver)
+}
+
+// HasKnownDriver - check the database driver is supported
+func (config *DatabaseConfig) HasKnownDriver() bool {
+	switch config.GetDriver() {
+	case DriverMySQL, DriverPostgres:
+		return true
+	}
+	return false
+}
+
+// GetDSN - data source name for the configured driver
+func (config *DatabaseConfig) GetDSN() string {
+	if config.GetDriver() == DriverPostgres {
+		return config.GetPostgresConfigDSN()
+	}
+	return config.GetMysqlConfigDSN()
+}
+
+// GetRestoreDSN - data source name of the connection restoring a dump, the MySQL client reads max_allowed_packet
+// from the server on connect instead of using its 4MB default
+func (config *DatabaseConfig) GetRestoreDSN() string {
+	if config.GetDriver() == DriverPostgres {
+		return config.GetPostgresConfigDSN()
+	}
+	mysqlConfig := config.mysqlConfig()
+	mysqlConfig.MaxAllowedPacket = 0
+	return mysqlConfig.FormatDSN()
+}
+
 func (config *DatabaseConfig) GetMysqlConfigDSN() string {
+	return config.mysqlConfig().FormatDSN()
+}
+
+func (config *DatabaseConfig) mysqlConfig() *mysql.Config {
 	mysqlConfig := mysql.NewConfig()
 	mysqlConfig.DBName = config.DatabaseName
 	mysqlConfig.Net = config.Net
@@ -176,7 +461,37 @@ func (config *DatabaseConfig) GetMysqlConfigDSN() string {
 	case "unix":
 		mysqlConfig.Addr = config.Socket
 	}
-	return mysqlConfig.FormatDSN()
+	return mysqlConfig
+}
+
+// GetPostgresConfigDSN - libpq connection string, see https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING
+func (config *DatabaseConfig) GetPostgresConfigDSN() string {
+	params := [][]string{
+		{"dbname", config.DatabaseName},
+		{"user", config.User},
+		{"password", config.Password},
+		{"sslmode", config.SSLMode},
+	}
+	switch config.Net {
+	case "tcp":
+		params = append(params, []string{"host", config.Hostname}, []string{"port", config.Port})
+	case "unix":
+		// libpq expects a directory containing the socket file
+		params = append(params, []string{"host", config.Socket})
+	}
+
+	pairs := make([]string, 0, len(params))
+	for _, param := range params {
+		if param[1] != "" {
+			pairs = append(pairs, param[0]+"="+quoteConnParam(param[1]))
+		}
+	}
+	return strings.Join(pairs, " ")
+}
+
+func quoteConnParam(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return "'" + replacer.Replace(value) + "'"
 }
 
 func (config *Config) GetAllUniqueTableNames() []string {
@@ -187,6 +502,19 @@ func (config *Config) GetAllUniqueTableNames() []string {
 	return allTables
 }
 
+// ObfuscatedColumns - names of the obfuscated columns by table name
+func (config *Config) ObfuscatedColumns() map[string][]string {
+	columns := make(map[string][]string)
+	for t := range config.Tables.Obfuscate {
+		tableMap, _ := config.Tables.Obfuscate[t].(map[string]interface{})
+		for c := range tableMap {
+			columns[t] = append(columns[t], c)
+		}
+		sort.Strings(columns[t])
+	}
+	return columns
+}
+
 func (config *Config) getObfuscatedTableNames() []string {
 	tablesToObfuscate := make([]string, 0)
 	for t, _ := range config.Tables.Obfuscate {
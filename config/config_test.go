@@ -1,10 +1,15 @@
 package config
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 
 	"testing"
 	"time"
+
+	"github.com/vicdeo/go-obfuscate/faker"
 )
 
 type validatePair struct {
@@ -126,3 +131,297 @@ func TestGetMysqlConfigDSN(t *testing.T) {
 		}
 	}
 }
+
+type getDSNPair struct {
+	expectedDSN string
+	config      DatabaseConfig
+}
+
+var getDSNTestcases = []getDSNPair{
+	{
+		"dbuser:dbpass@tcp(127.0.0.1:3306)/black_mamba",
+		DatabaseConfig{Net: "tcp", DatabaseName: "black_mamba", User: "dbuser", Password: "dbpass", Hostname: "127.0.0.1", Port: "3306"},
+	},
+	{
+		"dbname='black_mamba' user='dbuser' password='db\\'pass' host='127.0.0.1' port='5432'",
+		DatabaseConfig{Driver: "postgres", Net: "tcp", DatabaseName: "black_mamba", User: "dbuser", Password: "db'pass", Hostname: "127.0.0.1", Port: "5432"},
+	},
+	{
+		"dbname='black_mamba' sslmode='disable' host='/run/postgresql'",
+		DatabaseConfig{Driver: "postgres", Net: "unix", DatabaseName: "black_mamba", Socket: "/run/postgresql", SSLMode: "disable"},
+	},
+}
+
+func TestGetDSN(t *testing.T) {
+	for _, testcase := range getDSNTestcases {
+		dsn := testcase.config.GetDSN()
+		if testcase.expectedDSN != dsn {
+			t.Error("Expected ", testcase.expectedDSN, " got ", dsn)
+		}
+	}
+}
+
+func TestGetRestoreDSN(t *testing.T) {
+	config := DatabaseConfig{Net: "tcp", DatabaseName: "black_mamba", User: "dbuser", Password: "dbpass", Hostname: "127.0.0.1", Port: "3306"}
+	expected := "dbuser:dbpass@tcp(127.0.0.1:3306)/black_mamba?maxAllowedPacket=0"
+	if dsn := config.GetRestoreDSN(); dsn != expected {
+		t.Error("Expected ", expected, " got ", dsn)
+	}
+}
+
+func TestGetNullString(t *testing.T) {
+	output := OutputConfig{}
+	if null := output.GetNullString(); null != `\N` {
+		t.Error("Expected \\N got ", null)
+	}
+	empty := ""
+	output.NullString = &empty
+	if null := output.GetNullString(); null != "" {
+		t.Error("Expected an empty string got ", null)
+	}
+}
+
+func TestGetColumnFakerDomain(t *testing.T) {
+	defer func(saved *Config) { conf = saved }(conf)
+	conf = &Config{
+		Tables: &TableConfig{
+			Obfuscate: map[string]interface{}{
+				"users": map[string]interface{}{
+					"name":  map[string]interface{}{"type": "name", "domain": "customer_name"},
+					"email": map[string]interface{}{"type": "email"},
+				},
+			},
+		},
+	}
+
+	mapped, ok := GetColumnFaker("users", "name").(*faker.MappedGenerator)
+	if !ok {
+		t.Fatal("Expected a mapped generator for a column with a domain")
+	}
+	if mapped.Domain != faker.GetDomain("customer_name") {
+		t.Error("Expected the customer_name domain, got", mapped.Domain.Name)
+	}
+	if _, ok := GetColumnFaker("users", "email").(*faker.MappedGenerator); ok {
+		t.Error("Expected a plain generator for a column without a domain")
+	}
+}
+
+func TestValidateObfuscateSection(t *testing.T) {
+	config := Config{
+		Tables: &TableConfig{
+			Obfuscate: map[string]interface{}{
+				"users": map[string]interface{}{
+					"name":     map[string]interface{}{"type": "name"},
+					"code":     map[string]interface{}{"type": "pattern", "pattern": "[A-Z"},
+					"nickname": nil,
+				},
+				"orders": map[string]interface{}{
+					"reference": map[string]interface{}{"type": "template", "template": "{{nickname}}"},
+				},
+			},
+		},
+	}
+
+	messages, hasErrors := config.ValidateObfuscateSection()
+	if !hasErrors {
+		t.Error("Expected errors for invalid columns")
+	}
+	expected := map[string]string{
+		"users.code":       "pattern is invalid: error parsing regexp: missing closing ]: `[A-Z`",
+		"users.nickname":   "type is missing",
+		"orders.reference": "placeholder {{nickname}}: unknown type nickname",
+	}
+	if len(messages) != len(expected) {
+		t.Error("Expected", len(expected), "messages, got", messages)
+	}
+	for _, message := range messages {
+		if expected[message[0]+"."+message[1]] != message[2] {
+			t.Error("Unexpected message", message)
+		}
+	}
+}
+
+func TestValidateObfuscateSectionWarnings(t *testing.T) {
+	config := Config{
+		Tables: &TableConfig{
+			Obfuscate: map[string]interface{}{
+				"orders": map[string]interface{}{
+					"customer": map[string]interface{}{"type": "token"},
+				},
+			},
+		},
+	}
+
+	messages, hasErrors := config.ValidateObfuscateSection()
+	if hasErrors {
+		t.Error("Expected warnings not to be errors")
+	}
+	if len(messages) != 1 || messages[0][2] != "warning: salt is not set, the ids change between runs" {
+		t.Error("Expected the unsalted token to be reported, got", messages)
+	}
+}
+
+func TestDictionaryFileRelativeToConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "cities.txt"), []byte("Paris\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{
+		dir: dir,
+		Tables: &TableConfig{
+			Obfuscate: map[string]interface{}{
+				"users": map[string]interface{}{
+					"city": map[string]interface{}{"type": "dictionary", "file": "cities.txt"},
+				},
+			},
+		},
+	}
+	generator, err := config.buildColumnFaker("users", "city")
+	if err != nil {
+		t.Fatal("Expected the file next to the config, got", err)
+	}
+	if value := generator.GetData(); value != "Paris" {
+		t.Error("Expected Paris, got", value)
+	}
+
+	// The files of the nested types start at the config directory too
+	config.Tables.Obfuscate["users"] = map[string]interface{}{
+		"profile": map[string]interface{}{"type": "json", "paths": []interface{}{
+			map[interface{}]interface{}{"path": "city", "type": "dictionary", "file": "cities.txt"},
+		}},
+	}
+	generator, err = config.buildColumnFaker("users", "profile")
+	if err != nil {
+		t.Fatal("Expected the nested file next to the config, got", err)
+	}
+	if value := faker.Fake(generator, `{"city": "Berlin"}`); value != `{"city":"Paris"}` {
+		t.Error("Expected Paris, got", value)
+	}
+}
+
+func TestColumnLocale(t *testing.T) {
+	config := Config{
+		Locale: "fr",
+		Tables: &TableConfig{
+			Obfuscate: map[string]interface{}{
+				"users": map[string]interface{}{
+					"city":      map[string]interface{}{"type": "city"},
+					"birthcity": map[string]interface{}{"type": "city", "locale": "xx"},
+				},
+			},
+		},
+	}
+	options := config.columnOptions(map[string]interface{}{"type": "city"})
+	if options["locale"] != "fr" {
+		t.Error("Expected the global locale, got", options["locale"])
+	}
+	options = config.columnOptions(map[string]interface{}{"type": "city", "locale": "de"})
+	if options["locale"] != "de" {
+		t.Error("Expected the column locale, got", options["locale"])
+	}
+
+	messages, hasErrors := config.ValidateObfuscateSection()
+	if !hasErrors || len(messages) != 1 || messages[0][1] != "birthcity" {
+		t.Error("Expected the unsupported column locale to be reported, got", messages)
+	}
+
+	// The global locale is reported once, not by the columns using it
+	config.Locale = "xx"
+	messages, _ = config.ValidateObfuscateSection()
+	if len(messages) != 2 || messages[0][0] != "" || messages[0][1] != "locale" {
+		t.Error("Expected the unsupported global locale to be reported, got", messages)
+	}
+
+	config.Tables.Obfuscate = map[string]interface{}{}
+	if messages, hasErrors := config.ValidateObfuscateSection(); !hasErrors || len(messages) != 1 {
+		t.Error("Expected the unsupported global locale to be reported without columns, got", messages)
+	}
+}
+
+func TestColumnConditions(t *testing.T) {
+	config := Config{
+		Tables: &TableConfig{
+			Obfuscate: map[string]interface{}{
+				"users": map[string]interface{}{
+					"email":   map[string]interface{}{"type": "email", "unless": "email LIKE '%@ourcompany.com' OR is_staff = 1"},
+					"name":    map[string]interface{}{"type": "name", "when": "status = 'active"},
+					"phone":   map[string]interface{}{"type": "phone", "unless": 1},
+					"company": map[string]interface{}{"type": "company"},
+				},
+			},
+		},
+	}
+	generator, err := config.buildColumnFaker("users", "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := generator.(*faker.ConditionalGenerator); !ok {
+		t.Error("Expected a conditional generator, got", generator)
+	}
+
+	messages, _ := config.ValidateObfuscateSection()
+	expected := map[string]string{
+		"users.name":  "when is invalid: ' is not closed",
+		"users.phone": "unless must be an expression",
+	}
+	if len(messages) != len(expected) {
+		t.Error("Expected", len(expected), "messages, got", messages)
+	}
+	for _, message := range messages {
+		if expected[message[0]+"."+message[1]] != message[2] {
+			t.Error("Unexpected message", message)
+		}
+	}
+}
+
+func TestValidateExcludeSection(t *testing.T) {
+	config := Config{
+		Tables: &TableConfig{
+			Keep:     []string{"users", "orders", "invoices", "payments"},
+			Truncate: []string{"sessions"},
+			Exclude: map[string]interface{}{
+				"users":    "gdpr_deleted = 1",
+				"orders":   map[string]interface{}{"sql": "user_id IN (SELECT id FROM users WHERE gdpr_deleted = 1)"},
+				"invoices": "gdpr_deleted =",
+				"payments": map[string]interface{}{"where": "1"},
+				"sessions": "user_id IS NULL",
+				"user":     "gdpr_deleted = 1",
+			},
+		},
+	}
+	rule, err := config.excludeRule("users")
+	if err != nil || rule.Condition == nil || rule.SQL != "" {
+		t.Error("Expected a condition, got", rule, err)
+	}
+	rule, err = config.excludeRule("orders")
+	if err != nil || rule.Condition != nil || rule.SQL == "" {
+		t.Error("Expected an SQL predicate, got", rule, err)
+	}
+	if rule, _ := config.excludeRule("customers"); rule != nil {
+		t.Error("Expected no rule, got", rule)
+	}
+
+	messages, hasErrors := config.ValidateExcludeSection()
+	if !hasErrors {
+		t.Error("Expected errors for invalid rules")
+	}
+	expected := map[string]string{
+		"invoices": "condition is invalid: a column or a value is expected at the end",
+		"payments": "rule must be a condition or an sql predicate",
+		"sessions": "the table is neither kept nor obfuscated",
+		"user":     "the table is neither kept nor obfuscated",
+	}
+	if len(messages) != len(expected) {
+		t.Error("Expected", len(expected), "messages, got", messages)
+	}
+	for _, message := range messages {
+		if expected[message[0]] != message[1] {
+			t.Error("Unexpected message", message)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package faker
+
+import (
+	"errors"
+	"fmt"
+
+	anotherFake "github.com/pioz/faker"
+)
+
+// FakeChoice picks one of the values, a value with a bigger weight is picked more often
+// With Sample the values and weights are taken from the original data before the dump
+type FakeChoice struct {
+	Values  []interface{}
+	Weights []int64
+	Sample  bool
+
+	total int64
+}
+
+func newFakeChoice(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	fc := &FakeChoice{}
+	if option(fakeConfig, "sample") != nil {
+		var ok bool
+		if fc.Sample, ok = boolOption(fakeConfig, "sample"); !ok {
+			return nil, errors.New("sample must be true or false")
+		}
+	}
+
+	choices, _ := option(fakeConfig, "choices").([]interface{})
+	if len(choices) == 0 && !fc.Sample {
+		return nil, errors.New("choices are missing")
+	}
+	for i, choice := range choices {
+		value, weight := choice, int64(1)
+		// A choice is either a value or a value with a weight
+		if choiceMap, ok := stringMap(choice); ok {
+			// An explicit null is a value, a missing one is a typo
+			if value, ok = choiceMap["value"]; !ok {
+				return nil, fmt.Errorf("value of choice %d is missing", i+1)
+			}
+			if choiceMap["weight"] != nil {
+				w, ok := intOption(choiceMap, "weight")
+				if !ok || w < 0 {
+					return nil, fmt.Errorf("weight of choice %d must be a non-negative number", i+1)
+				}
+				weight = int64(w)
+			}
+		}
+		fc.Values = append(fc.Values, value)
+		fc.Weights = append(fc.Weights, weight)
+		fc.total += weight
+	}
+	if len(choices) != 0 && fc.total == 0 {
+		return nil, errors.New("at least one choice must have a positive weight")
+	}
+	return fc, nil
+}
+
+func (fc *FakeChoice) NeedsSample() bool {
+	return fc.Sample
+}
+
+func (fc *FakeChoice) SetSample(values []interface{}, counts []int64) {
+	fc.Values, fc.Weights, fc.total = values, counts, 0
+	for _, count := range counts {
+		fc.total += count
+	}
+}
+
+func (fc *FakeChoice) GetData() interface{} {
+	if fc.total <= 0 {
+		return nil
+	}
+	n := anotherFake.Int64InRange(0, fc.total)
+	for i, weight := range fc.Weights {
+		if n < weight {
+			return fc.Values[i]
+		}
+		n -= weight
+	}
+	return fc.Values[len(fc.Values)-1]
+}
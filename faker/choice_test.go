@@ -0,0 +1,50 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeChoiceWeights(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "choice", "choices": []interface{}{
+		map[interface{}]interface{}{"value": "active", "weight": 3},
+		map[string]interface{}{"value": "blocked", "weight": 0},
+		"inactive",
+	}})
+	assert.NoError(t, err)
+
+	counts := map[interface{}]int{}
+	for i := 0; i < 4000; i++ {
+		counts[generator.GetData()]++
+	}
+	assert.Len(t, counts, 2)
+	assert.InDelta(t, 3000, counts["active"], 200)
+	assert.InDelta(t, 1000, counts["inactive"], 200)
+}
+
+func TestFakeChoiceSample(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "choice", "sample": true})
+	assert.NoError(t, err)
+	sampler := GetSampler(NewLengthLimiter(generator, 10))
+	if assert.NotNil(t, sampler) {
+		sampler.SetSample([]interface{}{nil, "gold"}, []int64{1, 0})
+	}
+	assert.Nil(t, generator.GetData())
+
+	fixed, _ := Build(map[string]interface{}{"type": "choice", "choices": []interface{}{"a"}})
+	assert.Nil(t, GetSampler(fixed))
+}
+
+func TestFakeChoiceInvalid(t *testing.T) {
+	for expected, config := range map[string]map[string]interface{}{
+		"choices are missing":                              {"type": "choice"},
+		"weight of choice 1 must be a non-negative number": {"type": "choice", "choices": []interface{}{map[string]interface{}{"value": "a", "weight": -1}}},
+		"value of choice 2 is missing":                     {"type": "choice", "choices": []interface{}{"a", map[string]interface{}{"vaule": "b", "weight": 2}}},
+		"at least one choice must have a positive weight":  {"type": "choice", "choices": []interface{}{map[string]interface{}{"value": "a", "weight": 0}}},
+		"sample must be true or false":                     {"type": "choice", "sample": "sometimes"},
+	} {
+		_, err := Build(config)
+		assert.EqualError(t, err, expected)
+	}
+}
@@ -0,0 +1,573 @@
+package faker
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Condition is an expression on the original values of the row, e.g. email LIKE '%@example.com' OR is_staff = 1
+// It supports AND, OR, NOT, parentheses, =, !=, <>, <, <=, >, >=, [NOT] LIKE, [NOT] ILIKE, [NOT] IN (...), IS [NOT] NULL
+// and a column alone, which is true when its value is neither zero nor empty. Like in SQL, a comparison with NULL is unknown
+type Condition struct {
+	Expression string
+	root       condNode
+	columns    []string
+}
+
+// tri is the three-valued logic of SQL
+type tri int8
+
+const (
+	triFalse tri = iota
+	triTrue
+	triUnknown
+)
+
+type condNode interface {
+	eval(values map[string]interface{}) tri
+}
+
+// ParseCondition checks the expression and prepares it for the evaluation
+func ParseCondition(expression string) (*Condition, error) {
+	tokens, err := tokenizeCondition(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &condParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s", p.tokens[p.pos].text)
+	}
+	return &Condition{Expression: expression, root: root, columns: p.columns}, nil
+}
+
+// Matches tells whether the condition is true for the row, false if it is false or unknown
+func (c *Condition) Matches(row *Row) bool {
+	if row == nil {
+		return false
+	}
+	return c.root.eval(row.Values) == triTrue
+}
+
+// Columns lists the columns the condition refers to
+func (c *Condition) Columns() []string {
+	return c.columns
+}
+
+// MARK: tokens
+
+type condTokenKind int
+
+const (
+	tokenIdent condTokenKind = iota
+	tokenKeyword
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type condToken struct {
+	kind condTokenKind
+	text string
+}
+
+var condKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "LIKE": true, "ILIKE": true, "IN": true, "IS": true,
+	"NULL": true, "TRUE": true, "FALSE": true,
+}
+
+func tokenizeCondition(expression string) ([]condToken, error) {
+	var tokens []condToken
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '\'' || r == '`' || r == '"':
+			// Quotes are doubled or escaped with a backslash inside strings and quoted column names
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					// Like in MySQL \% and \_ keep the backslash to escape LIKE wildcards
+					if runes[j+1] == '%' || runes[j+1] == '_' {
+						b.WriteRune(runes[j])
+					}
+					j++
+				} else if runes[j] == r {
+					if j+1 < len(runes) && runes[j+1] == r {
+						j++
+					} else {
+						break
+					}
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("%c is not closed", r)
+			}
+			kind := tokenString
+			if r != '\'' {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, condToken{kind: kind, text: b.String()})
+			i = j + 1
+
+		case unicode.IsDigit(r) || (r == '-' || r == '.') && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			text := string(runes[i:j])
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, fmt.Errorf("%s is not a number", text)
+			}
+			tokens = append(tokens, condToken{kind: tokenNumber, text: text})
+			i = j
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '$') {
+				j++
+			}
+			text := string(runes[i:j])
+			if condKeywords[strings.ToUpper(text)] {
+				tokens = append(tokens, condToken{kind: tokenKeyword, text: strings.ToUpper(text)})
+			} else {
+				tokens = append(tokens, condToken{kind: tokenIdent, text: text})
+			}
+			i = j
+
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "!=", "<>", "<=", ">=":
+					op = two
+				}
+			}
+			switch op {
+			case "=", "!=", "<>", "<", "<=", ">", ">=", "(", ")", ",":
+			default:
+				return nil, fmt.Errorf("unexpected %s", op)
+			}
+			tokens = append(tokens, condToken{kind: tokenOperator, text: op})
+			i += len([]rune(op))
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	return tokens, nil
+}
+
+// MARK: parser
+
+type condParser struct {
+	tokens  []condToken
+	pos     int
+	columns []string
+}
+
+func (p *condParser) peek() *condToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+// accept skips the keyword or the operator if it is next
+func (p *condParser) accept(text string) bool {
+	if t := p.peek(); t != nil && (t.kind == tokenKeyword || t.kind == tokenOperator) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *condParser) expect(text string) error {
+	if !p.accept(text) {
+		return p.unexpected(text)
+	}
+	return nil
+}
+
+func (p *condParser) unexpected(expected string) error {
+	if t := p.peek(); t != nil {
+		return fmt.Errorf("%s is expected instead of %s", expected, t.text)
+	}
+	return fmt.Errorf("%s is expected at the end", expected)
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept("OR") {
+		var right condNode
+		if right, err = p.parseAnd(); err == nil {
+			left = &logicNode{and: false, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	left, err := p.parseNot()
+	for err == nil && p.accept("AND") {
+		var right condNode
+		if right, err = p.parseNot(); err == nil {
+			left = &logicNode{and: true, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *condParser) parseNot() (condNode, error) {
+	if p.accept("NOT") {
+		node, err := p.parseNot()
+		return &notNode{node}, err
+	}
+	return p.parsePredicate()
+}
+
+func (p *condParser) parsePredicate() (condNode, error) {
+	if p.accept("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("IS") {
+		not := p.accept("NOT")
+		return &nullNode{operand: left, not: not}, p.expect("NULL")
+	}
+	not := p.accept("NOT")
+	switch {
+	case p.accept("LIKE"), p.accept("ILIKE"):
+		insensitive := p.tokens[p.pos-1].text == "ILIKE"
+		pattern, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &likeNode{operand: left, pattern: pattern, insensitive: insensitive, not: not}, nil
+	case p.accept("IN"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		node := &inNode{operand: left, not: not}
+		for {
+			item, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			node.list = append(node.list, item)
+			if !p.accept(",") {
+				break
+			}
+		}
+		return node, p.expect(")")
+	case not:
+		return nil, p.unexpected("LIKE, ILIKE or IN")
+	}
+
+	if t := p.peek(); t != nil && t.kind == tokenOperator {
+		switch op := t.text; op {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.pos++
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return &compareNode{op: op, left: left, right: right}, nil
+		}
+	}
+	return &truthNode{left}, nil
+}
+
+func (p *condParser) parseOperand() (*operand, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, p.unexpected("a column or a value")
+	}
+	p.pos++
+	switch t.kind {
+	case tokenIdent:
+		p.columns = append(p.columns, t.text)
+		return &operand{column: t.text, isColumn: true}, nil
+	case tokenString:
+		return &operand{value: t.text}, nil
+	case tokenNumber:
+		f, _ := strconv.ParseFloat(t.text, 64)
+		return &operand{value: f}, nil
+	case tokenKeyword:
+		switch t.text {
+		case "NULL":
+			return &operand{}, nil
+		case "TRUE":
+			return &operand{value: float64(1)}, nil
+		case "FALSE":
+			return &operand{value: float64(0)}, nil
+		}
+	}
+	p.pos--
+	return nil, p.unexpected("a column or a value")
+}
+
+// MARK: evaluation
+
+type operand struct {
+	column   string
+	isColumn bool
+	value    interface{}
+}
+
+// get returns the value as string or float64, nil for NULL
+func (o *operand) get(values map[string]interface{}) interface{} {
+	if !o.isColumn {
+		return o.value
+	}
+	switch v := values[o.column].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	case bool:
+		if v {
+			return float64(1)
+		}
+		return float64(0)
+	case Decimal:
+		return string(v)
+	case nil:
+		return nil
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+type logicNode struct {
+	and         bool
+	left, right condNode
+}
+
+func (n *logicNode) eval(values map[string]interface{}) tri {
+	left, right := n.left.eval(values), n.right.eval(values)
+	if n.and {
+		switch {
+		case left == triFalse || right == triFalse:
+			return triFalse
+		case left == triTrue && right == triTrue:
+			return triTrue
+		}
+		return triUnknown
+	}
+	switch {
+	case left == triTrue || right == triTrue:
+		return triTrue
+	case left == triFalse && right == triFalse:
+		return triFalse
+	}
+	return triUnknown
+}
+
+type notNode struct {
+	node condNode
+}
+
+func (n *notNode) eval(values map[string]interface{}) tri {
+	return not(n.node.eval(values))
+}
+
+func not(value tri) tri {
+	switch value {
+	case triTrue:
+		return triFalse
+	case triFalse:
+		return triTrue
+	}
+	return triUnknown
+}
+
+func triOf(b bool) tri {
+	if b {
+		return triTrue
+	}
+	return triFalse
+}
+
+type compareNode struct {
+	op          string
+	left, right *operand
+}
+
+func (n *compareNode) eval(values map[string]interface{}) tri {
+	cmp, ok := compareOperands(n.left.get(values), n.right.get(values))
+	if !ok {
+		return triUnknown
+	}
+	switch n.op {
+	case "=":
+		return triOf(cmp == 0)
+	case "!=", "<>":
+		return triOf(cmp != 0)
+	case "<":
+		return triOf(cmp < 0)
+	case "<=":
+		return triOf(cmp <= 0)
+	case ">":
+		return triOf(cmp > 0)
+	}
+	return triOf(cmp >= 0)
+}
+
+// compareOperands compares numbers as numbers when the other side is a number too, strings as they are written
+func compareOperands(a, b interface{}) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	fa, aIsNumber := a.(float64)
+	fb, bIsNumber := b.(float64)
+	if aIsNumber != bIsNumber {
+		// A string compared with a number is read as a number
+		var err error
+		if aIsNumber {
+			fb, err = strconv.ParseFloat(strings.TrimSpace(b.(string)), 64)
+		} else {
+			fa, err = strconv.ParseFloat(strings.TrimSpace(a.(string)), 64)
+		}
+		aIsNumber = err == nil
+	}
+	if aIsNumber {
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	return strings.Compare(toString(a), toString(b)), true
+}
+
+type likeNode struct {
+	operand, pattern *operand
+	insensitive, not bool
+}
+
+func (n *likeNode) eval(values map[string]interface{}) tri {
+	value, pattern := n.operand.get(values), n.pattern.get(values)
+	if value == nil || pattern == nil {
+		return triUnknown
+	}
+	re, err := likeRegexp(toString(pattern), n.insensitive)
+	if err != nil {
+		return triUnknown
+	}
+	result := triOf(re.MatchString(formatOperand(value)))
+	if n.not {
+		return not(result)
+	}
+	return result
+}
+
+// likeRegexp converts the LIKE pattern, % is any string, _ is any character and \ escapes them
+func likeRegexp(pattern string, insensitive bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if insensitive {
+		b.WriteString("(?i)")
+	}
+	b.WriteString("(?s)^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case r == '%':
+			b.WriteString(".*")
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// formatOperand writes numbers without a useless fractional part
+func formatOperand(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return toString(value)
+}
+
+type inNode struct {
+	operand *operand
+	list    []*operand
+	not     bool
+}
+
+func (n *inNode) eval(values map[string]interface{}) tri {
+	value := n.operand.get(values)
+	result := triFalse
+	for _, item := range n.list {
+		cmp, ok := compareOperands(value, item.get(values))
+		if !ok {
+			result = triUnknown
+		} else if cmp == 0 {
+			result = triTrue
+			break
+		}
+	}
+	if n.not {
+		return not(result)
+	}
+	return result
+}
+
+type nullNode struct {
+	operand *operand
+	not     bool
+}
+
+func (n *nullNode) eval(values map[string]interface{}) tri {
+	return triOf((n.operand.get(values) == nil) != n.not)
+}
+
+type truthNode struct {
+	operand *operand
+}
+
+func (n *truthNode) eval(values map[string]interface{}) tri {
+	switch value := n.operand.get(values).(type) {
+	case nil:
+		return triUnknown
+	case float64:
+		return triOf(value != 0)
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return triOf(f != 0)
+		}
+		return triOf(value != "")
+	}
+	return triTrue
+}
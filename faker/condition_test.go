@@ -0,0 +1,112 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionMatches(t *testing.T) {
+	row := &Row{Table: "users", Values: map[string]interface{}{
+		"email":    "qa@OurCompany.com",
+		"is_staff": int64(1),
+		"score":    float64(7.5),
+		"code":     []byte("A-1"),
+		"status":   "active",
+		"deleted":  nil,
+		"active":   false,
+		"count":    "12",
+		"order":    "x",
+	}}
+	for expression, expected := range map[string]bool{
+		"email LIKE '%@ourcompany.com'":                   false,
+		"email ILIKE '%@ourcompany.com'":                  true,
+		"email NOT ILIKE '%@ourcompany.com'":              false,
+		"email like 'qa@%' and is_staff = 1":              true,
+		"is_staff = 0 OR status IN ('active', 'pending')": true,
+		"status NOT IN ('active')":                        false,
+		"score > 7 AND score <= 7.5":                      true,
+		"score <> 7.5":                                    false,
+		"count > 9":                                       true,
+		"count > '9'":                                     false,
+		"code = 'A-1'":                                    true,
+		"code LIKE 'A\\_1'":                               false,
+		"code LIKE 'A_1'":                                 true,
+		"deleted IS NULL":                                 true,
+		"deleted IS NOT NULL":                             false,
+		"deleted = 1":                                     false,
+		"NOT deleted = 1":                                 false,
+		"deleted = 1 OR is_staff":                         true,
+		"NOT (deleted = 1 AND is_staff = 0)":              true,
+		"deleted IN (1, 2)":                               false,
+		"status NOT IN ('blocked', NULL)":                 false,
+		"is_staff":                                        true,
+		"active":                                          false,
+		"NOT active":                                      true,
+		"active = FALSE":                                  true,
+		"`order` = 'x'":                                   true,
+		"status = 'it''s' OR status = 'it\\'s'":           false,
+		"score >= -1 AND score < .5":                      false,
+	} {
+		condition, err := ParseCondition(expression)
+		if assert.NoError(t, err, expression) {
+			assert.Equal(t, expected, condition.Matches(row), expression)
+		}
+	}
+
+	condition, _ := ParseCondition("is_staff = 1")
+	assert.False(t, condition.Matches(nil))
+}
+
+func TestConditionColumns(t *testing.T) {
+	condition, err := ParseCondition("email LIKE '%@example.com' OR (`is staff` = 1 AND 2 > level)")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"email", "is staff", "level"}, condition.Columns())
+}
+
+func TestConditionInvalid(t *testing.T) {
+	for expression, expected := range map[string]string{
+		"":                    "expression is empty",
+		"email = 'a":          "' is not closed",
+		"email == 'a'":        "a column or a value is expected instead of =",
+		"email LIKE":          "a column or a value is expected at the end",
+		"email NOT = 1":       "LIKE, ILIKE or IN is expected instead of =",
+		"(email = 1":          ") is expected at the end",
+		"email = 1)":          "unexpected )",
+		"email IS 1":          "NULL is expected instead of 1",
+		"email IN 1":          "( is expected instead of 1",
+		"email = 1 AND":       "a column or a value is expected at the end",
+		"email ~ 1":           "unexpected ~",
+		"score > 1.2.3":       "1.2.3 is not a number",
+		"email = 1 email = 2": "unexpected email",
+	} {
+		_, err := ParseCondition(expression)
+		assert.EqualError(t, err, expected, expression)
+	}
+}
+
+func TestConditionalGenerator(t *testing.T) {
+	unless, _ := ParseCondition("email LIKE '%@ourcompany.com' OR is_staff = 1")
+	when, _ := ParseCondition("status != 'deleted'")
+	generator := NewConditionalGenerator(&FakeFixed{Value: "fake"}, when, unless)
+
+	for _, testcase := range []struct {
+		values   map[string]interface{}
+		expected interface{}
+	}{
+		{map[string]interface{}{"email": "a@b.c", "is_staff": int64(0), "status": "active"}, "fake"},
+		{map[string]interface{}{"email": "qa@ourcompany.com", "is_staff": int64(0), "status": "active"}, "original"},
+		{map[string]interface{}{"email": "a@b.c", "is_staff": int64(1), "status": "active"}, "original"},
+		{map[string]interface{}{"email": "a@b.c", "is_staff": nil, "status": "active"}, "fake"},
+		{map[string]interface{}{"email": "a@b.c", "is_staff": int64(0), "status": "deleted"}, "original"},
+		{map[string]interface{}{"email": "a@b.c", "is_staff": int64(0), "status": nil}, "original"},
+	} {
+		assert.Equal(t, testcase.expected, FakeRow(generator, "original", &Row{Values: testcase.values}), testcase.values)
+	}
+	assert.Equal(t, "fake", generator.GetData())
+	assert.Equal(t, []string{"status", "email", "is_staff"}, ConditionColumns(NewLengthLimiter(generator, 10)))
+
+	plain := &FakeFixed{}
+	assert.Equal(t, plain, NewConditionalGenerator(plain, nil, nil))
+	assert.Nil(t, ConditionColumns(plain))
+}
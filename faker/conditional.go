@@ -0,0 +1,65 @@
+package faker
+
+// ConditionalGenerator obfuscates the rows matching When and not matching Unless, the other rows keep the original value
+// Either condition could be nil. Without a row, e.g. for GetData, the value is obfuscated
+type ConditionalGenerator struct {
+	Generator FakeGenerator
+	When      *Condition
+	Unless    *Condition
+}
+
+// NewConditionalGenerator wraps the generator, it is returned as is without conditions
+func NewConditionalGenerator(generator FakeGenerator, when, unless *Condition) FakeGenerator {
+	if generator == nil || when == nil && unless == nil {
+		return generator
+	}
+	return &ConditionalGenerator{Generator: generator, When: when, Unless: unless}
+}
+
+func (cg *ConditionalGenerator) Unwrap() FakeGenerator {
+	return cg.Generator
+}
+
+func (cg *ConditionalGenerator) GetData() interface{} {
+	return cg.TransformRow(nil, nil)
+}
+
+func (cg *ConditionalGenerator) TransformRow(original interface{}, row *Row) interface{} {
+	if !cg.Applies(row) {
+		return original
+	}
+	return FakeRow(cg.Generator, original, row)
+}
+
+// Applies tells whether the row is obfuscated
+func (cg *ConditionalGenerator) Applies(row *Row) bool {
+	if row == nil {
+		return true
+	}
+	if cg.When != nil && !cg.When.Matches(row) {
+		return false
+	}
+	return cg.Unless == nil || !cg.Unless.Matches(row)
+}
+
+// Columns lists the columns the conditions refer to
+func (cg *ConditionalGenerator) Columns() []string {
+	var columns []string
+	for _, condition := range []*Condition{cg.When, cg.Unless} {
+		if condition != nil {
+			columns = append(columns, condition.Columns()...)
+		}
+	}
+	return columns
+}
+
+// ConditionColumns lists the columns the conditions of the generator or of the wrapped ones refer to
+func ConditionColumns(generator FakeGenerator) []string {
+	var columns []string
+	for ; generator != nil; generator = Unwrap(generator) {
+		if conditional, ok := generator.(*ConditionalGenerator); ok {
+			columns = append(columns, conditional.Columns()...)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,223 @@
+package faker
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	anotherFake "github.com/pioz/faker"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04:05"
+)
+
+var (
+	// Layouts of the original values: MySQL strings and PostgreSQL times converted to strings by database/sql
+	parseLayouts = []string{
+		"2006-01-02 15:04:05.999999999",
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999Z07:00",
+		dateLayout,
+	}
+
+	columnPrecisionRe = regexp.MustCompile(`\((\d+)\)`)
+
+	// shiftKey makes shifts unpredictable between the runs while they stay the same within a run
+	shiftKey = anotherFake.Uint64()
+)
+
+// FakeDate is a random date in the range
+type FakeDate struct {
+	From   time.Time
+	To     time.Time
+	column Column
+}
+
+// FakeDateJitter moves the original date by up to Days days
+// All the jittered columns of a row are moved by the same number of days, so their order is kept
+type FakeDateJitter struct {
+	Days   int
+	column Column
+}
+
+// FakeDateShift moves the original date by up to Days days, the shift is the same for all the rows of an entity
+// so intervals between events of the entity are kept
+type FakeDateShift struct {
+	Days   int
+	Entity string
+	column Column
+}
+
+func newFakeDate(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	from := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Now().UTC()
+	var ok bool
+	if option(fakeConfig, "from") != nil {
+		if from, ok = timeOption(fakeConfig, "from"); !ok {
+			return nil, errors.New("from must be a date")
+		}
+	}
+	if option(fakeConfig, "to") != nil {
+		if to, ok = timeOption(fakeConfig, "to"); !ok {
+			return nil, errors.New("to must be a date")
+		}
+	}
+	if !to.After(from) {
+		return nil, errors.New("to must be after from")
+	}
+	return &FakeDate{From: from, To: to}, nil
+}
+
+func newFakeDateJitter(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	days, ok := intOption(fakeConfig, "days")
+	if !ok || days <= 0 {
+		return nil, errors.New("days must be a positive number")
+	}
+	return &FakeDateJitter{Days: days}, nil
+}
+
+func newFakeDateShift(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	days, ok := intOption(fakeConfig, "days")
+	if !ok || days <= 0 {
+		return nil, errors.New("days must be a positive number")
+	}
+	entity, _ := stringOption(fakeConfig, "entity")
+	if entity == "" {
+		return nil, errors.New("entity column is missing")
+	}
+	return &FakeDateShift{Days: days, Entity: entity}, nil
+}
+
+func (fd *FakeDate) SetColumn(column Column) {
+	fd.column = column
+}
+
+func (fd *FakeDate) GetData() interface{} {
+	t := time.Unix(anotherFake.Int64InRange(fd.From.Unix(), fd.To.Unix()+1), 0).UTC()
+	return t.Format(columnLayout(fd.column.Type, dateTimeLayout))
+}
+
+func (fj *FakeDateJitter) SetColumn(column Column) {
+	fj.column = column
+}
+
+// GetData has no date to move, a random one is not a jitter
+func (fj *FakeDateJitter) GetData() interface{} {
+	return nil
+}
+
+func (fj *FakeDateJitter) TransformRow(original interface{}, row *Row) interface{} {
+	if row == nil {
+		return moveTime(original, anotherFake.IntInRange(-fj.Days, fj.Days), fj.column)
+	}
+	return moveTime(original, offset(fj.Days, row.Table, strconv.FormatInt(row.Number, 10)), fj.column)
+}
+
+func (fs *FakeDateShift) SetColumn(column Column) {
+	fs.column = column
+}
+
+func (fs *FakeDateShift) GetData() interface{} {
+	return nil
+}
+
+func (fs *FakeDateShift) TransformRow(original interface{}, row *Row) interface{} {
+	var entity interface{}
+	if row != nil {
+		entity = row.Values[fs.Entity]
+	}
+	// The table is not a part of the key, so the entity is shifted the same way in all the tables
+	return moveTime(original, offset(fs.Days, "entity", fmt.Sprintf("%v", entity)), fs.column)
+}
+
+// EntityColumns lists the entity columns of the generator or of the wrapped ones
+func EntityColumns(generator FakeGenerator) []string {
+	var columns []string
+	for ; generator != nil; generator = Unwrap(generator) {
+		if shift, ok := generator.(*FakeDateShift); ok {
+			columns = append(columns, shift.Entity)
+		}
+	}
+	return columns
+}
+
+// offset derives a number of days in [-days, days] from the key
+func offset(days int, key ...string) int {
+	h := fnv.New64a()
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], shiftKey)
+	h.Write(b[:])
+	for _, k := range key {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+	}
+	return int(h.Sum64()%uint64(2*days+1)) - days
+}
+
+// moveTime adds days to the original date, values that are not dates (NULL, zero dates) are kept
+func moveTime(original interface{}, days int, column Column) interface{} {
+	var t time.Time
+	layout := dateTimeLayout
+	switch value := original.(type) {
+	case time.Time:
+		t = value
+	case string:
+		parsed, ok := parseTime(value)
+		if !ok {
+			return original
+		}
+		t = parsed
+		if len(value) == len(dateLayout) {
+			layout = dateLayout
+		}
+	default:
+		return original
+	}
+	return t.AddDate(0, 0, days).Format(columnLayout(column.Type, layout))
+}
+
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range parseLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// columnLayout returns the layout of values of the column type, fallback if the type is not a date
+func columnLayout(columnType, fallback string) string {
+	columnType = strings.ToLower(columnType)
+	switch {
+	case strings.HasPrefix(columnType, "date") && !strings.HasPrefix(columnType, "datetime"):
+		return dateLayout
+	case strings.HasPrefix(columnType, "timestamptz"), strings.Contains(columnType, "with time zone") && !strings.Contains(columnType, "without"):
+		return dateTimeLayout + fraction(columnType, 6) + "Z07:00"
+	case strings.HasPrefix(columnType, "timestamp"), strings.HasPrefix(columnType, "datetime"):
+		// PostgreSQL keeps microseconds unless told otherwise, MySQL keeps none
+		precision := 0
+		if strings.Contains(columnType, "time zone") {
+			precision = 6
+		}
+		return dateTimeLayout + fraction(columnType, precision)
+	}
+	return fallback
+}
+
+// fraction returns the fractional seconds layout of the column precision
+func fraction(columnType string, precision int) string {
+	if m := columnPrecisionRe.FindStringSubmatch(columnType); m != nil {
+		precision, _ = strconv.Atoi(m[1])
+	}
+	if precision <= 0 {
+		return ""
+	}
+	return "." + strings.Repeat("0", precision)
+}
@@ -0,0 +1,75 @@
+package faker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnLayout(t *testing.T) {
+	for columnType, expected := range map[string]string{
+		"date":                           "2006-01-02",
+		"datetime":                       "2006-01-02 15:04:05",
+		"datetime(3)":                    "2006-01-02 15:04:05.000",
+		"timestamp":                      "2006-01-02 15:04:05",
+		"timestamp without time zone":    "2006-01-02 15:04:05.000000",
+		"timestamp(0) without time zone": "2006-01-02 15:04:05",
+		"timestamp with time zone":       "2006-01-02 15:04:05.000000Z07:00",
+		"varchar(32)":                    "fallback",
+		"":                               "fallback",
+	} {
+		assert.Equal(t, expected, columnLayout(columnType, "fallback"), columnType)
+	}
+}
+
+func TestFakeDateInRange(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "date", "from": "2000-01-01", "to": "2000-01-31"})
+	SetColumn(generator, Column{Name: "birth_date", Type: "date"})
+	for i := 0; i < 100; i++ {
+		value := generator.GetData().(string)
+		d, err := time.Parse("2006-01-02", value)
+		assert.NoError(t, err)
+		assert.False(t, d.Before(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)), value)
+		assert.False(t, d.After(time.Date(2000, 1, 31, 0, 0, 0, 0, time.UTC)), value)
+	}
+	assert.Nil(t, New(map[string]interface{}{"type": "date", "from": "2000-01-31", "to": "2000-01-01"}))
+}
+
+func TestFakeDateJitterKeepsRowOrder(t *testing.T) {
+	created := New(map[string]interface{}{"type": "date_jitter", "days": 10})
+	updated := New(map[string]interface{}{"type": "date_jitter", "days": "10"})
+	SetColumn(created, Column{Name: "created_at", Type: "datetime"})
+
+	for number := int64(1); number <= 100; number++ {
+		row := &Row{Table: "orders", Number: number}
+		createdAt, err := time.Parse(dateTimeLayout, FakeRow(created, "2020-03-01 10:00:00", row).(string))
+		assert.NoError(t, err)
+		updatedAt, err := time.Parse(dateTimeLayout, FakeRow(updated, "2020-03-02 10:00:00", row).(string))
+		assert.NoError(t, err)
+		assert.Equal(t, 24*time.Hour, updatedAt.Sub(createdAt))
+		assert.True(t, createdAt.Sub(time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)) <= 10*24*time.Hour)
+	}
+
+	assert.Nil(t, FakeRow(created, nil, &Row{}))
+	assert.Equal(t, "0000-00-00 00:00:00", FakeRow(created, "0000-00-00 00:00:00", &Row{}))
+	assert.Nil(t, New(map[string]interface{}{"type": "date_jitter"}))
+}
+
+func TestFakeDateShiftPerEntity(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "date_shift", "days": 365, "entity": "user_id"})
+	SetColumn(generator, Column{Name: "birth_date", Type: "date"})
+
+	first := FakeRow(generator, "1990-05-01", &Row{Table: "users", Values: map[string]interface{}{"user_id": int64(7)}}).(string)
+	second := FakeRow(generator, "1990-05-11", &Row{Table: "orders", Values: map[string]interface{}{"user_id": int64(7)}}).(string)
+	firstDate, _ := time.Parse(dateLayout, first)
+	secondDate, _ := time.Parse(dateLayout, second)
+	assert.Equal(t, 10*24*time.Hour, secondDate.Sub(firstDate))
+
+	// PostgreSQL times come as RFC 3339 strings
+	generator = New(map[string]interface{}{"type": "date_shift", "days": 1, "entity": "user_id"})
+	SetColumn(generator, Column{Name: "seen_at", Type: "timestamp with time zone"})
+	assert.Regexp(t, `^2020-03-0[123] 10:00:00\.000000Z$`, FakeRow(generator, "2020-03-02T10:00:00Z", &Row{}))
+
+	assert.Nil(t, New(map[string]interface{}{"type": "date_shift", "days": 30}))
+}
@@ -0,0 +1,145 @@
+package faker
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"strings"
+
+	anotherFake "github.com/pioz/faker"
+)
+
+// FakeDictionary picks a value from a file, either a value per line or a column of a CSV file with a header
+// Deterministic picks the same value for the same original one
+type FakeDictionary struct {
+	File          string
+	Column        string
+	Deterministic bool
+	values        []string
+}
+
+// dictionaries are loaded once per file and column
+var dictionaries = make(map[string][]string)
+
+func newFakeDictionary(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	file, _ := stringOption(fakeConfig, "file")
+	if file == "" {
+		return nil, errors.New("file is missing")
+	}
+	fd := &FakeDictionary{File: file}
+	if option(fakeConfig, "column") != nil {
+		var ok bool
+		if fd.Column, ok = stringOption(fakeConfig, "column"); !ok || fd.Column == "" {
+			return nil, errors.New("column must be a CSV header name")
+		}
+	}
+	if option(fakeConfig, "deterministic") != nil {
+		var ok bool
+		if fd.Deterministic, ok = boolOption(fakeConfig, "deterministic"); !ok {
+			return nil, errors.New("deterministic must be true or false")
+		}
+	}
+
+	values, err := loadDictionary(fd.File, fd.Column)
+	if err != nil {
+		return nil, err
+	}
+	fd.values = values
+	return fd, nil
+}
+
+func loadDictionary(file, column string) ([]string, error) {
+	key := file + "\x00" + column
+	if values, ok := dictionaries[key]; ok {
+		return values, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var values []string
+	if column == "" {
+		values, err = readLines(f)
+	} else {
+		values, err = readCSVColumn(f, column)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s: no values found", file)
+	}
+	dictionaries[key] = values
+	return values, nil
+}
+
+// readLines reads non-empty lines
+func readLines(r io.Reader) ([]string, error) {
+	var values []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); strings.TrimSpace(line) != "" {
+			values = append(values, line)
+		}
+	}
+	return values, scanner.Err()
+}
+
+// readCSVColumn reads non-empty values of the column named in the header
+func readCSVColumn(r io.Reader, column string) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	for i, name := range header {
+		if strings.TrimSpace(name) == column {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("column %s is not found in the header", column)
+	}
+
+	var values []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if index < len(record) && record[index] != "" {
+			values = append(values, record[index])
+		}
+	}
+}
+
+func (fd *FakeDictionary) GetData() interface{} {
+	return fd.values[anotherFake.IntInRange(0, len(fd.values)-1)]
+}
+
+func (fd *FakeDictionary) Transform(original interface{}) interface{} {
+	if !fd.Deterministic {
+		return fd.GetData()
+	}
+	if original == nil {
+		return nil
+	}
+	h := fnv.New64a()
+	h.Write([]byte(toString(original)))
+	return fd.values[h.Sum64()%uint64(len(fd.values))]
+}
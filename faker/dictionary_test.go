@@ -0,0 +1,81 @@
+package faker
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeDictionary(t *testing.T, name, content string) string {
+	file := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+	return file
+}
+
+func TestFakeDictionaryLines(t *testing.T) {
+	file := writeDictionary(t, "cities.txt", "Paris\r\n\nLyon\n  \nNice\n")
+	generator, err := Build(map[string]interface{}{"type": "dictionary", "file": file})
+	assert.NoError(t, err)
+
+	seen := map[interface{}]bool{}
+	for i := 0; i < 200; i++ {
+		seen[generator.GetData()] = true
+	}
+	assert.Equal(t, map[interface{}]bool{"Paris": true, "Lyon": true, "Nice": true}, seen)
+}
+
+func TestFakeDictionaryCSVColumn(t *testing.T) {
+	file := writeDictionary(t, "people.csv", "id,name\n1,\"Doe, John\"\n2,\n3,Jane\n")
+	generator, err := Build(map[string]interface{}{"type": "dictionary", "file": file, "column": "name"})
+	assert.NoError(t, err)
+
+	seen := map[interface{}]bool{}
+	for i := 0; i < 200; i++ {
+		seen[generator.GetData()] = true
+	}
+	assert.Equal(t, map[interface{}]bool{"Doe, John": true, "Jane": true}, seen)
+}
+
+func TestFakeDictionaryDeterministic(t *testing.T) {
+	file := writeDictionary(t, "words.txt", "alpha\nbeta\ngamma\ndelta\n")
+	generator, err := Build(map[string]interface{}{"type": "dictionary", "file": file, "deterministic": true})
+	assert.NoError(t, err)
+
+	first := Fake(generator, "original")
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, Fake(generator, "original"))
+		assert.Equal(t, Fake(generator, []byte("other")), Fake(generator, "other"))
+	}
+	assert.Nil(t, Fake(generator, nil))
+}
+
+func TestFakeDictionaryLoadedOnce(t *testing.T) {
+	file := writeDictionary(t, "once.txt", "first\n")
+	_, err := Build(map[string]interface{}{"type": "dictionary", "file": file})
+	assert.NoError(t, err)
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte("second\n"), 0644))
+	generator, err := Build(map[string]interface{}{"type": "dictionary", "file": file})
+	assert.NoError(t, err)
+	assert.Equal(t, "first", generator.GetData())
+}
+
+func TestFakeDictionaryInvalid(t *testing.T) {
+	empty := writeDictionary(t, "empty.txt", "\n\n")
+	csv := writeDictionary(t, "header.csv", "id,name\n1,Jane\n")
+	for expected, config := range map[string]map[string]interface{}{
+		"file is missing":                                 {"type": "dictionary"},
+		"column must be a CSV header name":                {"type": "dictionary", "file": csv, "column": ""},
+		"deterministic must be true or false":             {"type": "dictionary", "file": csv, "deterministic": "maybe"},
+		empty + ": no values found":                       {"type": "dictionary", "file": empty},
+		csv + ": column email is not found in the header": {"type": "dictionary", "file": csv, "column": "email"},
+	} {
+		_, err := Build(config)
+		assert.EqualError(t, err, expected)
+	}
+
+	_, err := Build(map[string]interface{}{"type": "dictionary", "file": filepath.Join(t.TempDir(), "missing.txt")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package faker
+
+import "fmt"
+
+// Domain is a dictionary of original to fake values shared by columns of different tables
+type Domain struct {
+	Name string
+
+	values map[string]interface{}
+	issued map[string]struct{}
+}
+
+// MappedGenerator returns the same fake value for the same original value within its domain
+type MappedGenerator struct {
+	Generator FakeGenerator
+	Domain    *Domain
+
+	err error
+}
+
+// Taken fake values are generated again this many times before the generator gives up
+const maxDomainAttempts = 100
+
+var domains = make(map[string]*Domain)
+
+// GetDomain returns the named domain, it lives as long as the program runs
+func GetDomain(name string) *Domain {
+	domain, ok := domains[name]
+	if !ok {
+		domain = &Domain{
+			Name:   name,
+			values: make(map[string]interface{}),
+			issued: make(map[string]struct{}),
+		}
+		domains[name] = domain
+	}
+	return domain
+}
+
+// NewMappedGenerator makes the generator share the domain dictionary, nil is returned as is
+func NewMappedGenerator(generator FakeGenerator, domain *Domain) FakeGenerator {
+	if generator == nil {
+		return nil
+	}
+	return &MappedGenerator{
+		Generator: generator,
+		Domain:    domain,
+	}
+}
+
+func (mg *MappedGenerator) GetData() interface{} {
+	return mg.Generator.GetData()
+}
+
+func (mg *MappedGenerator) Unwrap() FakeGenerator {
+	return mg.Generator
+}
+
+// TransformRow looks the original value up in the domain, a new one gets a fake value no other original has
+// NULL is not mapped, NULL is returned and Err is set when no free fake value is found
+func (mg *MappedGenerator) TransformRow(original interface{}, row *Row) interface{} {
+	if original == nil {
+		return FakeRow(mg.Generator, original, row)
+	}
+	key := fmt.Sprintf("%v", original)
+	if value, ok := mg.Domain.values[key]; ok {
+		return value
+	}
+
+	for i := 0; i < maxDomainAttempts; i++ {
+		value := FakeRow(mg.Generator, original, row)
+		if _, ok := mg.Domain.issued[fmt.Sprintf("%v", value)]; !ok {
+			mg.Domain.values[key] = value
+			mg.Domain.issued[fmt.Sprintf("%v", value)] = struct{}{}
+			return value
+		}
+	}
+	mg.err = fmt.Errorf("no free value of domain %s is found in %d attempts after %d values, the generator is exhausted", mg.Domain.Name, maxDomainAttempts, len(mg.Domain.issued))
+	return nil
+}
+
+func (mg *MappedGenerator) Err() error {
+	return mg.err
+}
@@ -0,0 +1,39 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMappedGeneratorSharesDomain(t *testing.T) {
+	names := NewMappedGenerator(&sequence{values: []interface{}{"Alice", "Bob", "Carol"}}, GetDomain("test_shared"))
+	billingNames := NewMappedGenerator(&sequence{values: []interface{}{"Dave"}}, GetDomain("test_shared"))
+
+	assert.Equal(t, "Alice", Fake(names, "John Smith"))
+	assert.Equal(t, "Bob", Fake(names, "Jane Doe"))
+	assert.Equal(t, "Alice", Fake(billingNames, "John Smith"))
+	assert.Equal(t, "Dave", Fake(billingNames, "Jim Beam"))
+	assert.Equal(t, "Carol", Fake(names, nil))
+}
+
+func TestMappedGeneratorAvoidsTakenValues(t *testing.T) {
+	generator := NewMappedGenerator(&sequence{values: []interface{}{"Alice", "Alice", "Bob"}}, GetDomain("test_taken"))
+
+	assert.Equal(t, "Alice", Fake(generator, int64(1)))
+	assert.Equal(t, "Bob", Fake(generator, int64(2)))
+}
+
+func TestMappedGeneratorExhausted(t *testing.T) {
+	generator := NewMappedGenerator(&FakeFixed{Value: "Alice"}, GetDomain("test_exhausted"))
+
+	assert.Equal(t, "Alice", Fake(generator, int64(1)))
+	assert.Equal(t, "Alice", Fake(generator, int64(1)))
+	assert.NoError(t, generator.(Failing).Err())
+	assert.Nil(t, Fake(generator, int64(2)))
+	assert.EqualError(t, generator.(Failing).Err(), "no free value of domain test_exhausted is found in 100 attempts after 1 values, the generator is exhausted")
+}
+
+func TestFakeWithoutTransformer(t *testing.T) {
+	assert.Equal(t, "value", Fake(&FakeFixed{Value: "value"}, "original"))
+}
@@ -0,0 +1,375 @@
+package faker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultExternalBatch   = 100
+	defaultExternalTimeout = 10 * time.Second
+	// maxExternalLine is the longest reply line of the command
+	maxExternalLine = 64 * 1024 * 1024
+)
+
+// FakeExternal replaces the original values with the ones of an external command
+// The command is started once per table for the first value and shared by the columns of the table that run it.
+// It reads a JSON line per batch of a column on stdin:
+// {"table": "users", "column": "email", "values": ["a@b.c", null]}
+// It writes a JSON line with the replacements in the same order on stdout, or the reason of the failure:
+// {"values": ["x@y.z", null]} or {"error": "..."}
+type FakeExternal struct {
+	Command []string
+	Batch   int
+	Timeout time.Duration
+
+	column  Column
+	table   string
+	process *externalProcess
+	// pending keeps the prepared replacements by original value
+	pending map[string][]interface{}
+	err     error
+}
+
+// externalProcess is a running command shared by the columns of a table
+type externalProcess struct {
+	key     string
+	command []string
+	timeout time.Duration
+	// refs counts the columns that use the process, the last one to close stops it
+	refs    int
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	replies chan []byte
+	stopped chan struct{}
+	err     error
+}
+
+var (
+	externalProcesses      = map[string]*externalProcess{}
+	externalProcessesMutex sync.Mutex
+)
+
+type externalRequest struct {
+	Table  string        `json:"table"`
+	Column string        `json:"column"`
+	Values []interface{} `json:"values"`
+}
+
+type externalReply struct {
+	Values []interface{} `json:"values"`
+	Error  string        `json:"error"`
+}
+
+func newFakeExternal(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	fe := &FakeExternal{Batch: defaultExternalBatch, Timeout: defaultExternalTimeout}
+	switch command := option(fakeConfig, "command").(type) {
+	case string:
+		fe.Command = strings.Fields(command)
+	case []interface{}:
+		for _, arg := range command {
+			s, ok := arg.(string)
+			if !ok {
+				return nil, errors.New("command arguments must be strings")
+			}
+			fe.Command = append(fe.Command, s)
+		}
+	}
+	if len(fe.Command) == 0 {
+		return nil, errors.New("command is missing")
+	}
+	if _, err := exec.LookPath(fe.Command[0]); err != nil {
+		return nil, fmt.Errorf("command %s is not found", fe.Command[0])
+	}
+
+	if option(fakeConfig, "batch") != nil {
+		var ok bool
+		if fe.Batch, ok = intOption(fakeConfig, "batch"); !ok || fe.Batch <= 0 {
+			return nil, errors.New("batch must be a positive number")
+		}
+	}
+	if option(fakeConfig, "timeout") != nil {
+		seconds, ok := floatOption(fakeConfig, "timeout")
+		if !ok || seconds <= 0 {
+			return nil, errors.New("timeout must be a positive number of seconds")
+		}
+		fe.Timeout = time.Duration(seconds * float64(time.Second))
+	}
+	return fe, nil
+}
+
+func (fe *FakeExternal) SetColumn(column Column) {
+	fe.column = column
+}
+
+func (fe *FakeExternal) BatchSize() int {
+	return fe.Batch
+}
+
+// Err returns the reason why the command could not transform the values
+func (fe *FakeExternal) Err() error {
+	return fe.err
+}
+
+// Prepare sends the original values of the upcoming rows to the command
+func (fe *FakeExternal) Prepare(table string, originals []interface{}) error {
+	fe.table = table
+	replacements, err := fe.request(originals)
+	if err != nil {
+		return err
+	}
+	// All the rows of the previous batch are done, values skipped by a wrapper are dropped
+	fe.pending = make(map[string][]interface{}, len(originals))
+	for i, original := range originals {
+		key := externalKey(original)
+		fe.pending[key] = append(fe.pending[key], replacements[i])
+	}
+	return nil
+}
+
+// GetData needs the original value
+func (fe *FakeExternal) GetData() interface{} {
+	return nil
+}
+
+func (fe *FakeExternal) Transform(original interface{}) interface{} {
+	key := externalKey(original)
+	if queue := fe.pending[key]; len(queue) > 0 {
+		if len(queue) == 1 {
+			delete(fe.pending, key)
+		} else {
+			fe.pending[key] = queue[1:]
+		}
+		return queue[0]
+	}
+	// Values that are not prepared, e.g. the ones generated again by a wrapper, are sent one by one
+	replacements, err := fe.request([]interface{}{original})
+	if err != nil {
+		return nil
+	}
+	return replacements[0]
+}
+
+// Close releases the command, it is stopped once every column of the table is done, the error tells whether it failed
+func (fe *FakeExternal) Close() error {
+	if fe.process == nil {
+		return fe.err
+	}
+	process := fe.process
+	fe.process = nil
+	fe.pending = nil
+	if err := process.release(); err != nil && fe.err == nil {
+		fe.err = err
+	}
+	return fe.err
+}
+
+// request sends the values to the command and returns its replacements
+func (fe *FakeExternal) request(values []interface{}) ([]interface{}, error) {
+	if fe.err != nil {
+		return nil, fe.err
+	}
+	if fe.process == nil {
+		if fe.process, fe.err = acquireExternalProcess(fe.table, fe.Command, fe.Timeout); fe.err != nil {
+			return nil, fe.err
+		}
+	}
+
+	request := externalRequest{Table: fe.table, Column: fe.column.Name, Values: make([]interface{}, len(values))}
+	for i, value := range values {
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		request.Values[i] = value
+	}
+	replacements, err := fe.process.request(request, fe.Timeout)
+	if err != nil {
+		fe.err = err
+		return nil, err
+	}
+	return replacements, nil
+}
+
+// acquireExternalProcess returns the process running the command for the table, it is started on the first call
+func acquireExternalProcess(table string, command []string, timeout time.Duration) (*externalProcess, error) {
+	externalProcessesMutex.Lock()
+	defer externalProcessesMutex.Unlock()
+
+	key := table + "\x00" + strings.Join(command, "\x00")
+	process, ok := externalProcesses[key]
+	if !ok {
+		process = &externalProcess{key: key, command: command, timeout: timeout}
+		if err := process.start(); err != nil {
+			return nil, err
+		}
+		externalProcesses[key] = process
+	}
+	process.refs++
+	return process, nil
+}
+
+// release stops the process when no column uses it anymore
+func (p *externalProcess) release() error {
+	externalProcessesMutex.Lock()
+	defer externalProcessesMutex.Unlock()
+
+	p.refs--
+	if p.refs > 0 {
+		return p.err
+	}
+	delete(externalProcesses, p.key)
+	return p.stop()
+}
+
+func (p *externalProcess) request(request externalRequest, timeoutAfter time.Duration) ([]interface{}, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	line, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := time.NewTimer(timeoutAfter)
+	defer timeout.Stop()
+	written := make(chan error, 1)
+	go func() {
+		_, err := p.stdin.Write(append(line, '\n'))
+		written <- err
+	}()
+	select {
+	case err := <-written:
+		if err != nil {
+			return nil, p.fail(fmt.Errorf("command %s does not read values: %v", p.command[0], err))
+		}
+	case <-timeout.C:
+		return nil, p.fail(fmt.Errorf("command %s does not read values in %s", p.command[0], timeoutAfter))
+	}
+
+	var replyLine []byte
+	select {
+	case reply, ok := <-p.replies:
+		if !ok {
+			return nil, p.fail(fmt.Errorf("command %s exited before the reply", p.command[0]))
+		}
+		replyLine = reply
+	case <-timeout.C:
+		return nil, p.fail(fmt.Errorf("command %s does not reply in %s", p.command[0], timeoutAfter))
+	}
+
+	var reply externalReply
+	decoder := json.NewDecoder(bytes.NewReader(replyLine))
+	decoder.UseNumber()
+	if err := decoder.Decode(&reply); err != nil {
+		return nil, p.fail(fmt.Errorf("command %s reply is not valid JSON: %v", p.command[0], err))
+	}
+	if reply.Error != "" {
+		return nil, p.fail(fmt.Errorf("command %s: %s", p.command[0], reply.Error))
+	}
+	if len(reply.Values) != len(request.Values) {
+		return nil, p.fail(fmt.Errorf("command %s replied %d values to %d", p.command[0], len(reply.Values), len(request.Values)))
+	}
+	for i, value := range reply.Values {
+		reply.Values[i] = externalValue(value)
+	}
+	return reply.Values, nil
+}
+
+func (p *externalProcess) start() error {
+	cmd := exec.Command(p.command[0], p.command[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("command %s: %v", p.command[0], err)
+	}
+
+	replies := make(chan []byte)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(replies)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(nil, maxExternalLine)
+		for scanner.Scan() {
+			select {
+			case replies <- append([]byte{}, scanner.Bytes()...):
+			case <-stopped:
+				return
+			}
+		}
+	}()
+	p.cmd, p.stdin, p.replies, p.stopped = cmd, stdin, replies, stopped
+	return nil
+}
+
+// stop closes stdin and waits for the command to exit, the error tells whether it failed
+func (p *externalProcess) stop() error {
+	if p.cmd == nil {
+		return p.err
+	}
+	cmd := p.cmd
+	p.cmd = nil
+	p.stdin.Close()
+	close(p.stopped)
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	select {
+	case err := <-exited:
+		if err != nil && p.err == nil {
+			p.err = fmt.Errorf("command %s failed: %v", p.command[0], err)
+		}
+	case <-time.After(p.timeout):
+		cmd.Process.Kill()
+		<-exited
+		if p.err == nil {
+			p.err = fmt.Errorf("command %s did not exit in %s", p.command[0], p.timeout)
+		}
+	}
+	return p.err
+}
+
+// fail keeps the error and stops the command, the following values of every column are not sent
+func (p *externalProcess) fail(err error) error {
+	p.err = err
+	if p.cmd != nil {
+		p.cmd.Process.Kill()
+		p.stop()
+	}
+	return err
+}
+
+// externalKey identifies the original value among the prepared ones
+func externalKey(original interface{}) string {
+	if b, ok := original.([]byte); ok {
+		original = string(b)
+	}
+	return fmt.Sprintf("%T %v", original, original)
+}
+
+// externalValue converts a JSON value of the reply, numbers are kept as they are written
+func externalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool:
+		return v
+	case json.Number:
+		return Decimal(v)
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
@@ -0,0 +1,145 @@
+package faker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExternalHelperProcess is the external command of the tests, it is not a real test
+func TestExternalHelperProcess(t *testing.T) {
+	if os.Getenv("GO_OBFUSCATE_HELPER_PROCESS") != "1" {
+		return
+	}
+	mode := os.Args[len(os.Args)-1]
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request externalRequest
+		json.Unmarshal(scanner.Bytes(), &request)
+		switch mode {
+		case "error":
+			fmt.Println(`{"error": "no masking rule"}`)
+			continue
+		case "sleep":
+			time.Sleep(time.Minute)
+		case "short":
+			fmt.Println(`{"values": []}`)
+			continue
+		case "pid":
+			// The columns that share the process get the same id
+			for i := range request.Values {
+				request.Values[i] = fmt.Sprintf("%s:%d", request.Column, os.Getpid())
+			}
+			b, _ := json.Marshal(externalReply{Values: request.Values})
+			fmt.Println(string(b))
+			continue
+		}
+		// Batches are counted in the replies to check the values are sent at once
+		for i, value := range request.Values {
+			if s, ok := value.(string); ok {
+				request.Values[i] = fmt.Sprintf("%s:%s:%d:%s", request.Table, request.Column, len(request.Values), strings.ToUpper(s))
+			}
+		}
+		b, _ := json.Marshal(externalReply{Values: request.Values})
+		fmt.Println(string(b))
+	}
+	os.Exit(0)
+}
+
+func helperCommand(t *testing.T, mode string, options map[string]interface{}) *FakeExternal {
+	return helperColumnCommand(t, "email", mode, options)
+}
+
+func helperColumnCommand(t *testing.T, column, mode string, options map[string]interface{}) *FakeExternal {
+	os.Setenv("GO_OBFUSCATE_HELPER_PROCESS", "1")
+	config := map[string]interface{}{
+		"type":    "external",
+		"command": []interface{}{os.Args[0], "-test.run=^TestExternalHelperProcess$", "--", mode},
+	}
+	for k, v := range options {
+		config[k] = v
+	}
+	generator, err := Build(config)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	generator.(*FakeExternal).SetColumn(Column{Name: column})
+	return generator.(*FakeExternal)
+}
+
+func TestFakeExternalBatch(t *testing.T) {
+	defer os.Unsetenv("GO_OBFUSCATE_HELPER_PROCESS")
+	generator := helperCommand(t, "upper", nil)
+
+	assert.NoError(t, generator.Prepare("users", []interface{}{"a", []byte("b"), nil, "a", int64(7)}))
+	assert.Equal(t, "users:email:5:A", Fake(generator, "a"))
+	assert.Equal(t, "users:email:5:B", Fake(generator, []byte("b")))
+	assert.Nil(t, Fake(generator, nil))
+	assert.Equal(t, "users:email:5:A", Fake(generator, "a"))
+	assert.Equal(t, Decimal("7"), Fake(generator, int64(7)))
+	// Values that are not prepared are sent one by one
+	assert.Equal(t, "users:email:1:A", Fake(generator, "a"))
+
+	assert.NoError(t, generator.Close())
+	assert.NoError(t, generator.Err())
+}
+
+func TestFakeExternalProcessIsShared(t *testing.T) {
+	defer os.Unsetenv("GO_OBFUSCATE_HELPER_PROCESS")
+	email := helperColumnCommand(t, "email", "pid", nil)
+	name := helperColumnCommand(t, "name", "pid", nil)
+
+	assert.NoError(t, email.Prepare("users", []interface{}{"a"}))
+	assert.NoError(t, name.Prepare("users", []interface{}{"b"}))
+	emailValue, nameValue := Fake(email, "a").(string), Fake(name, "b").(string)
+	assert.True(t, strings.HasPrefix(emailValue, "email:"), emailValue)
+	assert.Equal(t, strings.TrimPrefix(emailValue, "email:"), strings.TrimPrefix(nameValue, "name:"))
+
+	// The process of the table keeps running until the last column is done
+	assert.NoError(t, email.Close())
+	assert.Equal(t, nameValue, Fake(name, "c"))
+	assert.NoError(t, name.Close())
+	assert.Empty(t, externalProcesses)
+
+	// Another table gets its own process
+	assert.NoError(t, email.Prepare("orders", []interface{}{"a"}))
+	assert.NotEqual(t, emailValue, Fake(email, "a"))
+	assert.NoError(t, email.Close())
+}
+
+func TestFakeExternalErrors(t *testing.T) {
+	defer os.Unsetenv("GO_OBFUSCATE_HELPER_PROCESS")
+	for mode, expected := range map[string]string{
+		"error": "no masking rule",
+		"short": "replied 0 values to 2",
+		"sleep": "does not reply in 100ms",
+	} {
+		generator := helperCommand(t, mode, map[string]interface{}{"timeout": 0.1})
+		err := generator.Prepare("users", []interface{}{"a", "b"})
+		if assert.Error(t, err, mode) {
+			assert.Contains(t, err.Error(), expected)
+		}
+		assert.Equal(t, err, generator.Err())
+		assert.Nil(t, Fake(generator, "c"))
+		assert.Equal(t, err, generator.Close())
+	}
+}
+
+func TestFakeExternalInvalid(t *testing.T) {
+	for expected, config := range map[string]map[string]interface{}{
+		"command is missing":                           {"type": "external"},
+		"command arguments must be strings":            {"type": "external", "command": []interface{}{"cat", 1}},
+		"command go-obfuscate-missing is not found":    {"type": "external", "command": "go-obfuscate-missing --mask"},
+		"batch must be a positive number":              {"type": "external", "command": "cat", "batch": 0},
+		"timeout must be a positive number of seconds": {"type": "external", "command": "cat", "timeout": "soon"},
+	} {
+		_, err := Build(config)
+		assert.EqualError(t, err, expected)
+	}
+}
@@ -1,26 +1,59 @@
 package faker
 
 import (
+	"errors"
+	"fmt"
+
 	oneFake "github.com/manveru/faker"
 	anotherFake "github.com/pioz/faker"
 )
 
 const (
-	TypeFirstName   = "first_name"
-	TypeLastName    = "last_name"
-	TypeName        = "name"
-	TypePhone       = "phone"
-	TypeEmail       = "email"
-	TypeCompanyName = "company"
-	TypeAddress     = "address"
-	TypeStreet      = "street_address"
-	TypeCity        = "city"
-	TypeZipCode     = "zip_code"
-	TypeIPv4        = "ipv4"
-	TypeURL         = "url"
-	TypeLorem       = "lorem"
-	TypeFixed       = "fixed"
-	TypeString      = "string"
+	TypeFirstName    = "first_name"
+	TypeLastName     = "last_name"
+	TypeName         = "name"
+	TypePhone        = "phone"
+	TypeEmail        = "email"
+	TypeCompanyName  = "company"
+	TypeAddress      = "address"
+	TypeStreet       = "street_address"
+	TypeCity         = "city"
+	TypeZipCode      = "zip_code"
+	TypeIPv4         = "ipv4"
+	TypeURL          = "url"
+	TypeLorem        = "lorem"
+	TypeFixed        = "fixed"
+	TypeString       = "string"
+	TypeDate         = "date"
+	TypeDateJitter   = "date_jitter"
+	TypeDateShift    = "date_shift"
+	TypeNumber       = "number"
+	TypeNoise        = "noise"
+	TypeRound        = "round"
+	TypeMask         = "mask"
+	TypeMaskEmail    = "mask_email"
+	TypeHash         = "hash"
+	TypeToken        = "token"
+	TypePattern      = "pattern"
+	TypeTemplate     = "template"
+	TypeChoice       = "choice"
+	TypeDictionary   = "dictionary"
+	TypeExternal     = "external"
+	TypeJSON         = "json"
+	TypePHP          = "php_serialized"
+	TypeQueryString  = "query_string"
+	TypeNull         = "null"
+	TypeDefault      = "default"
+	TypePasswordHash = "password_hash"
+	TypeCreditCard   = "credit_card"
+	TypeIBAN         = "iban"
+	TypeSSN          = "ssn"
+	TypeINSEE        = "insee"
+	TypeUUID         = "uuid"
+	TypeIPv6         = "ipv6"
+	TypeMAC          = "mac"
+	TypeUserAgent    = "user_agent"
+	TypeUsername     = "username"
 )
 
 var (
@@ -35,16 +68,17 @@ type FakeGenerator interface {
 	GetData() interface{}
 }
 
-type FakeFirstName struct{}
-type FakeLastName struct{}
-type FakeName struct{}
-type FakePhone struct{}
+// Generators of names, phone numbers and addresses use the dictionary of their locale, English ones if it is nil
+type FakeFirstName struct{ locale *oneFake.Faker }
+type FakeLastName struct{ locale *oneFake.Faker }
+type FakeName struct{ locale *oneFake.Faker }
+type FakePhone struct{ locale *oneFake.Faker }
 type FakeEmail struct{}
 type FakeCompanyName struct{}
-type FakeAddress struct{}
-type FakeStreetAddress struct{}
-type FakeCity struct{}
-type FakeZipCode struct{}
+type FakeAddress struct{ locale *oneFake.Faker }
+type FakeStreetAddress struct{ locale *oneFake.Faker }
+type FakeCity struct{ locale *oneFake.Faker }
+type FakeZipCode struct{ locale *oneFake.Faker }
 type FakeIPv4 struct{}
 type FakeURL struct{}
 type FakeLorem struct{}
@@ -57,59 +91,136 @@ type FakeString struct {
 	Length int
 }
 
+// New creates the generator described by the column config, nil if the config is invalid
 func New(fakeConfig map[string]interface{}) FakeGenerator {
-	if fakeType, ok := fakeConfig["type"]; ok {
-
-		switch fakeType {
-		case TypeFirstName:
-			return &FakeFirstName{}
-		case TypeLastName:
-			return &FakeLastName{}
-		case TypeName:
-			return &FakeName{}
-
-		case TypePhone:
-			return &FakePhone{}
-		case TypeEmail:
-			return &FakeEmail{}
-
-		case TypeCompanyName:
-			return &FakeCompanyName{}
-		case TypeAddress:
-			return &FakeAddress{}
-		case TypeStreet:
-			return &FakeStreetAddress{}
-		case TypeCity:
-			return &FakeCity{}
-		case TypeZipCode:
-			return &FakeZipCode{}
-
-		case TypeIPv4:
-			return &FakeIPv4{}
-		case TypeURL:
-			return &FakeURL{}
-		case TypeLorem:
-			return &FakeLorem{}
-
-		case TypeFixed:
-			var value string
-			if fixedValue, ok := fakeConfig["string"]; ok {
-				value = fixedValue.(string)
-			}
-			return &FakeFixed{
-				Value: value,
-			}
-		case TypeString:
-			var length int
-			if stringLength, ok := fakeConfig["length"]; ok {
-				length = stringLength.(int)
+	generator, err := Build(fakeConfig)
+	if err != nil {
+		return nil
+	}
+	return generator
+}
+
+// Build creates the generator described by the column config, the error tells what is wrong with the config
+func Build(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	fakeType, ok := fakeConfig["type"]
+	if !ok {
+		return nil, errors.New("type is missing")
+	}
+	if fakeType == nil {
+		// YAML reads an unquoted null as no value
+		return nil, errors.New("type is missing, quote \"null\" to write NULL")
+	}
+	name, _ := fakeType.(string)
+	factory, ok := factories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %v", fakeType)
+	}
+	// The locale is checked for any type as the top-level one applies to every column
+	if _, err := localeFaker(fakeConfig); err != nil {
+		return nil, err
+	}
+	return factory(fakeConfig)
+}
+
+func init() {
+	for name, factory := range map[string]Factory{
+		TypeFirstName: localeFactory(func(lf *oneFake.Faker) FakeGenerator { return &FakeFirstName{lf} }),
+		TypeLastName:  localeFactory(func(lf *oneFake.Faker) FakeGenerator { return &FakeLastName{lf} }),
+		TypeName:      localeFactory(func(lf *oneFake.Faker) FakeGenerator { return &FakeName{lf} }),
+		TypePhone:     localeFactory(func(lf *oneFake.Faker) FakeGenerator { return &FakePhone{lf} }),
+		TypeAddress:   localeFactory(func(lf *oneFake.Faker) FakeGenerator { return &FakeAddress{lf} }),
+		TypeStreet:    localeFactory(func(lf *oneFake.Faker) FakeGenerator { return &FakeStreetAddress{lf} }),
+		TypeCity:      localeFactory(func(lf *oneFake.Faker) FakeGenerator { return &FakeCity{lf} }),
+		TypeZipCode:   localeFactory(func(lf *oneFake.Faker) FakeGenerator { return &FakeZipCode{lf} }),
+		TypeUsername:  localeFactory(func(lf *oneFake.Faker) FakeGenerator { return &FakeUsername{lf} }),
+
+		TypeEmail:       simpleFactory(func() FakeGenerator { return &FakeEmail{} }),
+		TypeCompanyName: simpleFactory(func() FakeGenerator { return &FakeCompanyName{} }),
+		TypeIPv4:        simpleFactory(func() FakeGenerator { return &FakeIPv4{} }),
+		TypeURL:         simpleFactory(func() FakeGenerator { return &FakeURL{} }),
+		TypeLorem:       simpleFactory(func() FakeGenerator { return &FakeLorem{} }),
+		TypeIPv6:        simpleFactory(func() FakeGenerator { return &FakeIPv6{} }),
+		TypeMAC:         simpleFactory(func() FakeGenerator { return &FakeMAC{} }),
+		TypeUserAgent:   simpleFactory(func() FakeGenerator { return &FakeUserAgent{} }),
+		TypeSSN:         simpleFactory(func() FakeGenerator { return &FakeSSN{} }),
+		TypeINSEE:       simpleFactory(func() FakeGenerator { return &FakeINSEE{} }),
+
+		TypeFixed:  newFakeFixed,
+		TypeString: newFakeString,
+
+		TypeDate:       newFakeDate,
+		TypeDateJitter: newFakeDateJitter,
+		TypeDateShift:  newFakeDateShift,
+
+		TypeNumber: newFakeNumber,
+		TypeNoise:  newFakeNoise,
+		TypeRound:  newFakeRound,
+
+		TypeMask: func(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+			mask, err := newFakeMask(fakeConfig)
+			if err != nil {
+				return nil, err
 			}
-			return &FakeString{
-				Length: length,
+			return mask, nil
+		},
+		TypeMaskEmail: func(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+			mask, err := newFakeMask(fakeConfig)
+			if err != nil {
+				return nil, err
 			}
+			return &FakeMaskEmail{FakeMask: *mask}, nil
+		},
+
+		TypeHash:  newFakeHash,
+		TypeToken: newFakeToken,
+
+		TypePattern:  newFakePattern,
+		TypeTemplate: newFakeTemplate,
+
+		TypeChoice:      newFakeChoice,
+		TypeDictionary:  newFakeDictionary,
+		TypeExternal:    newFakeExternal,
+		TypeJSON:        newFakeJSON,
+		TypePHP:         newFakePHPSerialized,
+		TypeQueryString: newFakeQueryString,
+
+		TypeNull:    simpleFactory(func() FakeGenerator { return &FakeNull{} }),
+		TypeDefault: simpleFactory(func() FakeGenerator { return &FakeDefault{} }),
+
+		TypePasswordHash: newFakePasswordHash,
+
+		TypeCreditCard: newFakeCreditCard,
+		TypeIBAN:       newFakeIBAN,
+		TypeUUID:       newFakeUUID,
+	} {
+		Register(name, factory)
+	}
+}
+
+func newFakeFixed(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	var value string
+	if option(fakeConfig, "string") != nil {
+		var ok bool
+		if value, ok = stringOption(fakeConfig, "string"); !ok {
+			return nil, errors.New("string must be a string")
 		}
 	}
-	return nil
+	return &FakeFixed{
+		Value: value,
+	}, nil
+}
+
+func newFakeString(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	var length int
+	if option(fakeConfig, "length") != nil {
+		var ok bool
+		if length, ok = intOption(fakeConfig, "length"); !ok || length < 0 {
+			return nil, errors.New("length must be a positive number")
+		}
+	}
+	return &FakeString{
+		Length: length,
+	}, nil
 }
 
 func (ff *FakeFixed) GetData() interface{} {
@@ -121,18 +232,30 @@ func (fs *FakeString) GetData() interface{} {
 }
 
 func (ff *FakeFirstName) GetData() interface{} {
+	if ff.locale != nil {
+		return ff.locale.FirstName()
+	}
 	return anotherFake.FirstName()
 }
 
 func (ff *FakeLastName) GetData() interface{} {
+	if ff.locale != nil {
+		return ff.locale.LastName()
+	}
 	return anotherFake.LastName()
 }
 
 func (ff *FakeName) GetData() interface{} {
+	if ff.locale != nil {
+		return localeName(ff.locale)
+	}
 	return anotherFake.FullName()
 }
 
 func (ff *FakePhone) GetData() interface{} {
+	if ff.locale != nil {
+		return ff.locale.PhoneNumber()
+	}
 	return externalFakeGenerator.PhoneNumber()
 }
 
@@ -146,18 +269,30 @@ func (ff *FakeCompanyName) GetData() interface{} {
 }
 
 func (ff *FakeAddress) GetData() interface{} {
+	if ff.locale != nil {
+		return localeAddress(ff.locale)
+	}
 	return anotherFake.AddressFull()
 }
 
 func (ff *FakeStreetAddress) GetData() interface{} {
+	if ff.locale != nil {
+		return ff.locale.StreetAddress()
+	}
 	return anotherFake.AddressSecondaryAddress()
 }
 
 func (ff *FakeCity) GetData() interface{} {
+	if ff.locale != nil {
+		return ff.locale.City()
+	}
 	return anotherFake.AddressCity()
 }
 
 func (ff *FakeZipCode) GetData() interface{} {
+	if ff.locale != nil {
+		return ff.locale.PostCode()
+	}
 	return anotherFake.AddressZip()
 }
 
@@ -0,0 +1,162 @@
+package faker
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+const (
+	hashAlgorithmSHA256 = "sha256"
+	hashAlgorithmHMAC   = "hmac-sha256"
+
+	hashEncodingHex    = "hex"
+	hashEncodingBase64 = "base64"
+
+	defaultTokenLength = 16
+)
+
+var (
+	// tokenEncoding gives lowercase letters and digits only
+	tokenEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+	// tokenKey keeps tokens stable within a run when no salt is set
+	tokenKey = randomKey()
+)
+
+// FakeHash replaces the original value with its SHA-256 or HMAC-SHA256 digest
+// The same original gives the same hash, so hashed keys still join
+// Salt is required, a digest of a known email or phone number is found by hashing it
+type FakeHash struct {
+	HMAC     bool
+	Salt     string
+	Encoding string
+	// Length truncates the encoded digest if positive
+	Length int
+}
+
+// FakeToken replaces the original value with Prefix and an opaque id that is the same for the same original
+type FakeToken struct {
+	Prefix string
+	Length int
+	key    []byte
+	salted bool
+}
+
+func newFakeHash(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	algorithm, ok := stringOption(fakeConfig, "algorithm")
+	if !ok {
+		algorithm = hashAlgorithmSHA256
+	}
+	encoding, ok := stringOption(fakeConfig, "encoding")
+	if !ok {
+		encoding = hashEncodingHex
+	}
+	salt, _ := stringOption(fakeConfig, "salt")
+	// Length is optional, the whole digest is kept without it
+	length, lengthOk := intOption(fakeConfig, "length")
+	lengthOk = lengthOk && length > 0 || option(fakeConfig, "length") == nil
+
+	switch {
+	case algorithm != hashAlgorithmSHA256 && algorithm != hashAlgorithmHMAC:
+		return nil, fmt.Errorf("algorithm must be %s or %s", hashAlgorithmSHA256, hashAlgorithmHMAC)
+	case encoding != hashEncodingHex && encoding != hashEncodingBase64:
+		return nil, fmt.Errorf("encoding must be %s or %s", hashEncodingHex, hashEncodingBase64)
+	case salt == "":
+		return nil, errors.New("salt is missing, an unsalted digest of a known value is found by hashing the value")
+	case !lengthOk:
+		return nil, errors.New("length must be a positive number")
+	}
+	return &FakeHash{
+		HMAC:     algorithm == hashAlgorithmHMAC,
+		Salt:     salt,
+		Encoding: encoding,
+		Length:   length,
+	}, nil
+}
+
+func newFakeToken(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	prefix, _ := stringOption(fakeConfig, "prefix")
+	length, ok := intOption(fakeConfig, "length")
+	if !ok && option(fakeConfig, "length") == nil {
+		length = defaultTokenLength
+	}
+	// Longer ids would be cut from a single digest
+	if maxLength := tokenEncoding.EncodedLen(sha256.Size); length <= 0 || length > maxLength {
+		return nil, fmt.Errorf("length must be between 1 and %d", maxLength)
+	}
+	generator := &FakeToken{Prefix: prefix, Length: length, key: tokenKey}
+	if salt, ok := stringOption(fakeConfig, "salt"); ok && salt != "" {
+		generator.key = []byte(salt)
+		generator.salted = true
+	}
+	return generator, nil
+}
+
+// GetData has nothing to hash
+func (fh *FakeHash) GetData() interface{} {
+	return nil
+}
+
+func (fh *FakeHash) Transform(original interface{}) interface{} {
+	if original == nil {
+		return nil
+	}
+	var digest []byte
+	if fh.HMAC {
+		mac := hmac.New(sha256.New, []byte(fh.Salt))
+		mac.Write([]byte(toString(original)))
+		digest = mac.Sum(nil)
+	} else {
+		sum := sha256.Sum256([]byte(fh.Salt + toString(original)))
+		digest = sum[:]
+	}
+
+	var encoded string
+	if fh.Encoding == hashEncodingBase64 {
+		encoded = base64.StdEncoding.EncodeToString(digest)
+	} else {
+		encoded = hex.EncodeToString(digest)
+	}
+	if fh.Length > 0 && fh.Length < len(encoded) {
+		encoded = encoded[:fh.Length]
+	}
+	return encoded
+}
+
+// GetData returns a random token
+func (ft *FakeToken) GetData() interface{} {
+	return ft.token(randomKey())
+}
+
+func (ft *FakeToken) Transform(original interface{}) interface{} {
+	if original == nil {
+		return nil
+	}
+	return ft.token([]byte(toString(original)))
+}
+
+// Warning reminds that tokens of different dumps don't match without a salt
+func (ft *FakeToken) Warning() string {
+	if ft.salted {
+		return ""
+	}
+	return "salt is not set, the ids change between runs"
+}
+
+func (ft *FakeToken) token(value []byte) string {
+	mac := hmac.New(sha256.New, ft.key)
+	mac.Write(value)
+	return ft.Prefix + tokenEncoding.EncodeToString(mac.Sum(nil))[:ft.Length]
+}
+
+func randomKey() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
@@ -0,0 +1,53 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeHash(t *testing.T) {
+	for _, testcase := range []struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"salt": "pepper", "length": 12}, "711394d33945"},
+		{map[string]interface{}{"algorithm": "hmac-sha256", "salt": "key", "encoding": "base64"}, "kwezuRXvtRcf8U2MtV+8x5jGwO8UVtZt7RpqpyOli3s="},
+	} {
+		testcase.config["type"] = "hash"
+		generator := New(testcase.config)
+		assert.Equal(t, testcase.expected, Fake(generator, "hello"), testcase.config)
+		assert.Nil(t, Fake(generator, nil))
+	}
+
+	assert.Nil(t, New(map[string]interface{}{"type": "hash", "algorithm": "md5", "salt": "pepper"}))
+	assert.Nil(t, New(map[string]interface{}{"type": "hash", "encoding": "base32", "salt": "pepper"}))
+	// A plain digest of a known value is found by hashing the value
+	_, err := Build(map[string]interface{}{"type": "hash"})
+	assert.EqualError(t, err, "salt is missing, an unsalted digest of a known value is found by hashing the value")
+	_, err = Build(map[string]interface{}{"type": "hash", "algorithm": "hmac-sha256"})
+	assert.Error(t, err)
+	_, err = Build(map[string]interface{}{"type": "hash", "salt": "pepper", "length": "twelve"})
+	assert.EqualError(t, err, "length must be a positive number")
+	_, err = Build(map[string]interface{}{"type": "hash", "salt": "pepper", "length": -1})
+	assert.EqualError(t, err, "length must be a positive number")
+}
+
+func TestFakeToken(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "token", "prefix": "cus_"})
+	token := Fake(generator, int64(42))
+	assert.Regexp(t, `^cus_[a-z2-7]{16}$`, token)
+	assert.Equal(t, token, Fake(generator, "42"))
+	assert.NotEqual(t, token, Fake(generator, int64(43)))
+	assert.Regexp(t, `^cus_[a-z2-7]{16}$`, generator.GetData())
+
+	salted := New(map[string]interface{}{"type": "token", "salt": "stable", "length": 8})
+	assert.Equal(t, Fake(salted, "42"), Fake(New(map[string]interface{}{"type": "token", "salt": "stable", "length": 8}), "42"))
+	assert.Len(t, Fake(salted, "42"), 8)
+
+	assert.Equal(t, []string{"salt is not set, the ids change between runs"}, Warnings(generator))
+	assert.Empty(t, Warnings(salted))
+
+	assert.Nil(t, New(map[string]interface{}{"type": "token", "length": 100}))
+	assert.Nil(t, New(map[string]interface{}{"type": "token", "length": "long"}))
+}
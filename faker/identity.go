@@ -0,0 +1,118 @@
+package faker
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	oneFake "github.com/manveru/faker"
+	anotherFake "github.com/pioz/faker"
+)
+
+var (
+	// usernameLetters spells the accented letters of the de, fr and es names in ASCII
+	usernameLetters = strings.NewReplacer(
+		"à", "a", "á", "a", "â", "a", "ä", "ae", "ã", "a", "å", "a", "æ", "ae",
+		"ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+		"ì", "i", "í", "i", "î", "i", "ï", "i", "ñ", "n",
+		"ò", "o", "ó", "o", "ô", "o", "ö", "oe", "õ", "o", "œ", "oe",
+		"ù", "u", "ú", "u", "û", "u", "ü", "ue", "ÿ", "y", "ß", "ss",
+	)
+	// notUsername matches the characters usernames don't have, e.g. spaces and apostrophes of names
+	notUsername = regexp.MustCompile(`[^a-z0-9._]+`)
+)
+
+// FakeSSN generates US Social Security numbers like 123-45-6789 out of the ranges that are never assigned
+type FakeSSN struct{}
+
+// FakeINSEE generates French INSEE (NIR) numbers of 13 digits and a 2 digit key
+type FakeINSEE struct{}
+
+// FakeUUID generates random UUIDs of Version 4 or time ordered ones of Version 7
+type FakeUUID struct {
+	Version int
+}
+
+// FakeUsername generates usernames like john.smith or john42 from the names of the locale
+type FakeUsername struct{ locale *oneFake.Faker }
+
+func newFakeUUID(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	version := 4
+	if option(fakeConfig, "version") != nil {
+		var ok bool
+		if version, ok = intOption(fakeConfig, "version"); !ok || (version != 4 && version != 7) {
+			return nil, errors.New("version must be 4 or 7")
+		}
+	}
+	return &FakeUUID{Version: version}, nil
+}
+
+func (fs *FakeSSN) GetData() interface{} {
+	// Area 666 and 900-999 are never assigned
+	area := anotherFake.IntInRange(1, 898)
+	if area >= 666 {
+		area++
+	}
+	return fmt.Sprintf("%03d-%02d-%04d", area, anotherFake.IntInRange(1, 99), anotherFake.IntInRange(1, 9999))
+}
+
+func (fi *FakeINSEE) GetData() interface{} {
+	// Departments 2A and 2B of Corsica are not numbers, so 20 is skipped
+	department := anotherFake.IntInRange(1, 94)
+	if department >= 20 {
+		department++
+	}
+	number := fmt.Sprintf("%d%02d%02d%02d%03d%03d",
+		anotherFake.IntInRange(1, 2),
+		anotherFake.IntInRange(0, 99),
+		anotherFake.IntInRange(1, 12),
+		department,
+		anotherFake.IntInRange(1, 990),
+		anotherFake.IntInRange(1, 999))
+	return number + inseeKey(number)
+}
+
+// inseeKey computes the key of the 13 digit INSEE number
+func inseeKey(number string) string {
+	n, _ := strconv.ParseInt(number, 10, 64)
+	return fmt.Sprintf("%02d", 97-n%97)
+}
+
+func (fu *FakeUUID) GetData() interface{} {
+	var uuid [16]byte
+	rand.Read(uuid[:])
+	if fu.Version == 7 {
+		// The first 48 bits are the Unix time in milliseconds
+		var ms [8]byte
+		binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixNano()/int64(time.Millisecond)))
+		copy(uuid[:6], ms[2:])
+	}
+	uuid[6] = uuid[6]&0x0f | byte(fu.Version<<4)
+	// RFC 4122 variant
+	uuid[8] = uuid[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
+}
+
+func (fu *FakeUsername) GetData() interface{} {
+	var first, last string
+	if fu.locale != nil {
+		first, last = fu.locale.FirstName(), fu.locale.LastName()
+	} else {
+		first, last = anotherFake.FirstName(), anotherFake.LastName()
+	}
+	var username string
+	switch anotherFake.IntInRange(0, 2) {
+	case 0:
+		username = first + "." + last
+	case 1:
+		username = first + "_" + last
+	default:
+		username = first + strconv.Itoa(anotherFake.IntInRange(1, 999))
+	}
+	return notUsername.ReplaceAllString(usernameLetters.Replace(strings.ToLower(username)), "")
+}
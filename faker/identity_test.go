@@ -0,0 +1,64 @@
+package faker
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeSSN(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "ssn"})
+	for i := 0; i < 200; i++ {
+		ssn := generator.GetData().(string)
+		assert.Regexp(t, `^\d{3}-\d{2}-\d{4}$`, ssn)
+		area, _ := strconv.Atoi(ssn[:3])
+		assert.True(t, area > 0 && area != 666 && area < 900, ssn)
+		assert.NotEqual(t, "00", ssn[4:6], ssn)
+		assert.NotEqual(t, "0000", ssn[7:], ssn)
+	}
+}
+
+func TestFakeINSEE(t *testing.T) {
+	// The example of the INSEE documentation
+	assert.Equal(t, "91", inseeKey("1850578006084"))
+
+	generator := New(map[string]interface{}{"type": "insee"})
+	for i := 0; i < 200; i++ {
+		insee := generator.GetData().(string)
+		assert.Regexp(t, `^[12]\d\d(0[1-9]|1[0-2])\d{8}\d\d$`, insee)
+		n, _ := strconv.ParseInt(insee[:13], 10, 64)
+		key, _ := strconv.ParseInt(insee[13:], 10, 64)
+		assert.Equal(t, int64(0), (n+key)%97, insee)
+		assert.NotEqual(t, "20", insee[5:7], insee)
+	}
+}
+
+func TestFakeUUID(t *testing.T) {
+	v4 := New(map[string]interface{}{"type": "uuid"})
+	uuid := v4.GetData().(string)
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, uuid)
+	assert.NotEqual(t, uuid, v4.GetData())
+
+	before := time.Now().UnixNano() / int64(time.Millisecond)
+	uuid = New(map[string]interface{}{"type": "uuid", "version": 7}).GetData().(string)
+	after := time.Now().UnixNano() / int64(time.Millisecond)
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, uuid)
+	ms, _ := strconv.ParseInt(uuid[:8]+uuid[9:13], 16, 64)
+	assert.True(t, ms >= before && ms <= after, uuid)
+
+	_, err := Build(map[string]interface{}{"type": "uuid", "version": 1})
+	assert.EqualError(t, err, "version must be 4 or 7")
+}
+
+func TestFakeUsername(t *testing.T) {
+	for _, locale := range Locales() {
+		generator := New(map[string]interface{}{"type": "username", "locale": locale})
+		for i := 0; i < 50; i++ {
+			assert.Regexp(t, `^[a-z][a-z0-9._]*$`, generator.GetData(), locale)
+		}
+	}
+	assert.Equal(t, "francois.mueller", usernameLetters.Replace("françois.müller"))
+	assert.Equal(t, "eloise", usernameLetters.Replace("éloïse"))
+}
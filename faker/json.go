@@ -0,0 +1,183 @@
+package faker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FakeJSON applies generators to the values found at paths of a JSON document and keeps the rest of it
+// Values that are not valid JSON become NULL and are counted
+type FakeJSON struct {
+	Paths []*JSONPath
+
+	nested  []nestedGenerator
+	invalid int
+}
+
+// JSONPath is a path like contact.email, $.addresses[*].city or items[0].name with the generator of its values
+// [*] stands for every element of an array and * for every value of an object
+type JSONPath struct {
+	Path      string
+	Generator FakeGenerator
+	steps     []jsonStep
+}
+
+type jsonStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func newFakeJSON(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	nested, err := nestedGenerators(fakeConfig, "paths", "path")
+	if err != nil {
+		return nil, err
+	}
+
+	fj := &FakeJSON{nested: nested}
+	for _, n := range nested {
+		steps, err := parseJSONPath(n.name)
+		if err != nil {
+			return nil, err
+		}
+		fj.Paths = append(fj.Paths, &JSONPath{Path: n.name, Generator: n.generator, steps: steps})
+	}
+	return fj, nil
+}
+
+// parseJSONPath splits the path into the keys and the indexes
+func parseJSONPath(path string) ([]jsonStep, error) {
+	invalid := fmt.Errorf("path %q is invalid", path)
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	var steps []jsonStep
+	for first := true; rest != ""; first = false {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, invalid
+			}
+			inside := rest[1:end]
+			rest = rest[end+1:]
+			if inside == "*" {
+				steps = append(steps, jsonStep{isIndex: true, wildcard: true})
+				continue
+			}
+			index, err := strconv.Atoi(inside)
+			if err != nil || index < 0 {
+				return nil, invalid
+			}
+			steps = append(steps, jsonStep{isIndex: true, index: index})
+		case rest[0] == '.' || first:
+			if rest[0] == '.' {
+				rest = rest[1:]
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			rest = rest[end:]
+			if key == "" || strings.ContainsRune(key, ']') {
+				return nil, invalid
+			}
+			steps = append(steps, jsonStep{key: key, wildcard: key == "*"})
+		default:
+			return nil, invalid
+		}
+	}
+	if len(steps) == 0 {
+		return nil, invalid
+	}
+	return steps, nil
+}
+
+func (fj *FakeJSON) SetColumn(column Column) {
+	setNestedColumn(fj.nested, column)
+}
+
+// GetData needs the original document
+func (fj *FakeJSON) GetData() interface{} {
+	return nil
+}
+
+func (fj *FakeJSON) Invalid() int {
+	return fj.invalid
+}
+
+func (fj *FakeJSON) TransformRow(original interface{}, row *Row) interface{} {
+	if original == nil {
+		return nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(toString(original)))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		fj.invalid++
+		return nil
+	}
+
+	for _, path := range fj.Paths {
+		document = path.apply(document, path.steps, row)
+	}
+
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return nil
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// apply replaces the values found at the steps, missing keys and out of range indexes are skipped
+func (path *JSONPath) apply(node interface{}, steps []jsonStep, row *Row) interface{} {
+	if len(steps) == 0 {
+		return jsonValue(FakeRow(path.Generator, jsonOriginal(node), row))
+	}
+	step := steps[0]
+	switch value := node.(type) {
+	case []interface{}:
+		if !step.isIndex {
+			return node
+		}
+		for i := range value {
+			if step.wildcard || i == step.index {
+				value[i] = path.apply(value[i], steps[1:], row)
+			}
+		}
+	case map[string]interface{}:
+		if step.isIndex {
+			return node
+		}
+		for key, v := range value {
+			if step.wildcard || key == step.key {
+				value[key] = path.apply(v, steps[1:], row)
+			}
+		}
+	}
+	return node
+}
+
+// jsonOriginal gives JSON numbers to the generators as decimals
+func jsonOriginal(value interface{}) interface{} {
+	if number, ok := value.(json.Number); ok {
+		return Decimal(number)
+	}
+	return value
+}
+
+// jsonValue converts the fake value back to JSON, numbers are kept as they are written
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Decimal:
+		return json.Number(v)
+	case []byte:
+		return string(v)
+	}
+	return value
+}
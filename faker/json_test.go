@@ -0,0 +1,78 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeJSONPaths(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "json", "paths": []interface{}{
+		map[interface{}]interface{}{"path": "contact.email", "type": "fixed", "string": "x@example.test"},
+		map[interface{}]interface{}{"path": "$.addresses[*].city", "type": "fixed", "string": "Springfield"},
+		map[string]interface{}{"path": "phones[1]", "type": "mask"},
+		map[string]interface{}{"path": "scores.*", "type": "round", "step": 10},
+		map[string]interface{}{"path": "missing.key", "type": "fixed", "string": "never"},
+	}})
+	assert.NoError(t, err)
+
+	original := `{"contact":{"email":"john@doe.com","name":"John <Doe>"},` +
+		`"addresses":[{"city":"Paris","zip":"75001"},{"city":"Lyon"},{"zip":"13001"}],` +
+		`"phones":["0102030405","0607080910"],"scores":{"math":17.5,"art":42},"active":true}`
+	assert.JSONEq(t, `{"contact":{"email":"x@example.test","name":"John <Doe>"},`+
+		`"addresses":[{"city":"Springfield","zip":"75001"},{"city":"Springfield"},{"zip":"13001"}],`+
+		`"phones":["0102030405","**********"],"scores":{"math":20.0,"art":40},"active":true}`,
+		Fake(generator, []byte(original)).(string))
+	assert.Contains(t, Fake(generator, original), `"John <Doe>"`)
+	assert.Contains(t, Fake(generator, original), `"math":20.0`)
+}
+
+func TestFakeJSONRootArray(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "json", "paths": []interface{}{
+		map[string]interface{}{"path": "[*].email", "type": "mask_email"},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"email":"****@b.c"},{"email":null},"a@b.c"]`, Fake(generator, `[{"email":"john@b.c"},{"email":null},"a@b.c"]`))
+}
+
+func TestFakeJSONInvalidDocument(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "json", "paths": []interface{}{
+		map[string]interface{}{"path": "email", "type": "email"},
+	}})
+	assert.NoError(t, err)
+	assert.Nil(t, Fake(generator, nil))
+	assert.Nil(t, Fake(generator, "not json"))
+	assert.Nil(t, Fake(generator, `{"email":"a"} {}`))
+	assert.Nil(t, generator.GetData())
+	// NULL is not counted
+	assert.Equal(t, 2, generator.(InvalidCounter).Invalid())
+}
+
+func TestParseJSONPath(t *testing.T) {
+	steps, err := parseJSONPath("$.items[0][*].name")
+	assert.NoError(t, err)
+	assert.Equal(t, []jsonStep{
+		{key: "items"},
+		{isIndex: true, index: 0},
+		{isIndex: true, wildcard: true},
+		{key: "name"},
+	}, steps)
+
+	for _, path := range []string{"", "$", "a..b", "a[", "a[-1]", "a[x]", "a]b", "a.", "a[0]b"} {
+		_, err := parseJSONPath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestFakeJSONInvalid(t *testing.T) {
+	for expected, config := range map[string]map[string]interface{}{
+		"paths are missing":                      {"type": "json"},
+		"path 1 must have a path and a type":     {"type": "json", "paths": []interface{}{"email"}},
+		`path "a..b" is invalid`:                 {"type": "json", "paths": []interface{}{map[string]interface{}{"path": "a..b", "type": "email"}}},
+		"path email: unknown type mail":          {"type": "json", "paths": []interface{}{map[string]interface{}{"path": "email", "type": "mail"}}},
+		"path plan: type choice can't be nested": {"type": "json", "paths": []interface{}{map[string]interface{}{"path": "plan", "type": "choice", "sample": true}}},
+	} {
+		_, err := Build(config)
+		assert.EqualError(t, err, expected)
+	}
+}
@@ -0,0 +1,63 @@
+package faker
+
+import "unicode/utf8"
+
+// Fake values that don't fit are generated again this many times before they are truncated
+const maxLengthAttempts = 10
+
+// LengthLimiter keeps string values of a generator within the column maximum length
+type LengthLimiter struct {
+	Generator FakeGenerator
+	MaxLength int
+	// Truncated counts values that were cut to fit
+	Truncated int
+}
+
+// NewLengthLimiter wraps the generator, values are not limited if maxLength is not positive
+func NewLengthLimiter(generator FakeGenerator, maxLength int) FakeGenerator {
+	if generator == nil || maxLength <= 0 {
+		return generator
+	}
+	return &LengthLimiter{
+		Generator: generator,
+		MaxLength: maxLength,
+	}
+}
+
+func (ll *LengthLimiter) Unwrap() FakeGenerator {
+	return ll.Generator
+}
+
+func (ll *LengthLimiter) GetData() interface{} {
+	return ll.TransformRow(nil, nil)
+}
+
+func (ll *LengthLimiter) TransformRow(original interface{}, row *Row) interface{} {
+	var value string
+	for i := 0; i < maxLengthAttempts; i++ {
+		data := FakeRow(ll.Generator, original, row)
+		s, ok := data.(string)
+		if !ok {
+			// Only strings have a length
+			return data
+		}
+		if utf8.RuneCountInString(s) <= ll.MaxLength {
+			return s
+		}
+		value = s
+	}
+	ll.Truncated++
+	return truncate(value, ll.MaxLength)
+}
+
+// truncate cuts the string to maxLength characters
+func truncate(s string, maxLength int) string {
+	count := 0
+	for i := range s {
+		if count == maxLength {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sequence struct {
+	values []interface{}
+	next   int
+}
+
+func (s *sequence) GetData() interface{} {
+	value := s.values[s.next%len(s.values)]
+	s.next++
+	return value
+}
+
+func TestLengthLimiterRegenerates(t *testing.T) {
+	generator := NewLengthLimiter(&sequence{values: []interface{}{"too long", "fits"}}, 5)
+	assert.Equal(t, "fits", generator.GetData())
+	assert.Equal(t, 0, generator.(*LengthLimiter).Truncated)
+}
+
+func TestLengthLimiterTruncates(t *testing.T) {
+	generator := NewLengthLimiter(&sequence{values: []interface{}{"Zoë Zimmermann"}}, 3)
+	assert.Equal(t, "Zoë", generator.GetData())
+	assert.Equal(t, "Zoë", generator.GetData())
+	assert.Equal(t, 2, generator.(*LengthLimiter).Truncated)
+}
+
+func TestLengthLimiterSkipsUnlimited(t *testing.T) {
+	generator := &sequence{values: []interface{}{"value"}}
+	assert.Equal(t, generator, NewLengthLimiter(generator, 0))
+	assert.Nil(t, NewLengthLimiter(nil, 10))
+	assert.Equal(t, int64(42), NewLengthLimiter(&sequence{values: []interface{}{int64(42)}}, 1).GetData())
+}
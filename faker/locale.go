@@ -0,0 +1,142 @@
+package faker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	oneFake "github.com/manveru/faker"
+)
+
+// DefaultLocale keeps the English generators
+const DefaultLocale = "en"
+
+// locales are the supported locales with the dictionaries missing in github.com/manveru/faker
+var locales = map[string]map[string][]string{
+	DefaultLocale: nil,
+	"de":          nil,
+	"fr": {
+		"name.first_name": {
+			"Alice", "Antoine", "Camille", "Chloé", "Clément", "Élise", "Emma", "Étienne", "Gabriel", "Hugo",
+			"Inès", "Jade", "Jules", "Juliette", "Léa", "Léon", "Louis", "Louise", "Lucas", "Manon",
+			"Mathilde", "Nathan", "Noémie", "Paul", "Pauline", "Raphaël", "Sarah", "Théo", "Thomas", "Zoé",
+		},
+		"name.last_name": {
+			"Bernard", "Bonnet", "Dubois", "Durand", "Dupont", "Fontaine", "Fournier", "Garnier", "Girard", "Lambert",
+			"Laurent", "Lefebvre", "Lefèvre", "Leroy", "Martin", "Mercier", "Michel", "Moreau", "Morel", "Petit",
+			"Richard", "Robert", "Rousseau", "Roux", "Simon", "Thomas", "Vincent", "Faure", "André", "Chevalier",
+		},
+		"address.city": {
+			"Paris", "Marseille", "Lyon", "Toulouse", "Nice", "Nantes", "Montpellier", "Strasbourg", "Bordeaux", "Lille",
+			"Rennes", "Reims", "Toulon", "Grenoble", "Dijon", "Angers", "Nîmes", "Clermont-Ferrand", "Le Mans", "Aix-en-Provence",
+			"Brest", "Tours", "Amiens", "Limoges", "Annecy", "Perpignan", "Metz", "Besançon", "Orléans", "Rouen",
+		},
+		"address.street_prefix": {"Rue", "Avenue", "Boulevard", "Place", "Impasse", "Allée", "Chemin", "Quai"},
+		"address.street_root": {
+			"de la Paix", "Victor Hugo", "de la République", "du Général de Gaulle", "Jean Jaurès", "de la Gare",
+			"des Écoles", "Pasteur", "du Moulin", "de l'Église", "Gambetta", "des Lilas", "Émile Zola", "de la Liberté",
+		},
+		"address.street_name":     {"#{street_prefix} #{street_root}"},
+		"address.building_number": {"#", "##", "###", "# bis", "## bis"},
+		"address.street_address":  {"#{building_number} #{street_name}"},
+		"address.postcode": {
+			"75###", "13###", "69###", "31###", "06###", "44###", "34###", "67###", "33###", "59###", "35###", "38###",
+		},
+		"phone_number.formats": {
+			"01 ## ## ## ##", "02 ## ## ## ##", "03 ## ## ## ##", "04 ## ## ## ##", "05 ## ## ## ##",
+			"06 ## ## ## ##", "07 ## ## ## ##", "+33 6 ## ## ## ##", "+33 1 ## ## ## ##",
+		},
+	},
+	"es": {
+		"name.first_name": {
+			"Alejandro", "Ana", "Antonio", "Carlos", "Carmen", "Cristina", "David", "Diego", "Elena", "Francisco",
+			"Isabel", "Javier", "José", "Juan", "Laura", "Lucía", "Manuel", "María", "Marta", "Miguel",
+			"Pablo", "Paula", "Pedro", "Pilar", "Raquel", "Rosa", "Sara", "Sergio", "Sofía", "Teresa",
+		},
+		"name.last_name": {
+			"García", "Rodríguez", "González", "Fernández", "López", "Martínez", "Sánchez", "Pérez", "Gómez", "Martín",
+			"Jiménez", "Ruiz", "Hernández", "Díaz", "Moreno", "Muñoz", "Álvarez", "Romero", "Alonso", "Gutiérrez",
+			"Navarro", "Torres", "Domínguez", "Vázquez", "Ramos", "Gil", "Ramírez", "Serrano", "Blanco", "Molina",
+		},
+		"address.city": {
+			"Madrid", "Barcelona", "Valencia", "Sevilla", "Zaragoza", "Málaga", "Murcia", "Palma", "Bilbao", "Alicante",
+			"Córdoba", "Valladolid", "Vigo", "Gijón", "Granada", "A Coruña", "Vitoria", "Elche", "Oviedo", "Pamplona",
+			"Santander", "Almería", "San Sebastián", "Burgos", "Salamanca", "Logroño", "Badajoz", "Huelva", "Cádiz", "Toledo",
+		},
+		"address.street_prefix": {"Calle", "Avenida", "Plaza", "Paseo", "Camino", "Ronda", "Travesía"},
+		"address.street_root": {
+			"Mayor", "de la Constitución", "del Sol", "de Cervantes", "de Goya", "de la Paz", "Real", "del Carmen",
+			"de Alcalá", "de Velázquez", "San Juan", "de la Iglesia", "Nueva", "del Mar",
+		},
+		"address.street_name":     {"#{street_prefix} #{street_root}"},
+		"address.building_number": {"#", "##", "###", "s/n"},
+		"address.street_address":  {"#{street_name}, #{building_number}"},
+		"address.postcode": {
+			"28###", "08###", "46###", "41###", "50###", "29###", "30###", "07###", "48###", "03###", "14###", "47###",
+		},
+		"phone_number.formats": {
+			"6## ### ###", "7## ### ###", "91# ## ## ##", "93# ## ## ##", "95# ## ## ##", "96# ## ## ##", "+34 6## ### ###",
+		},
+	},
+}
+
+// localeFakers are created once per locale
+var localeFakers = make(map[string]*oneFake.Faker)
+
+func init() {
+	for locale, dictionary := range locales {
+		if dictionary != nil {
+			oneFake.Dict[locale] = dictionary
+		}
+	}
+}
+
+// Locales lists the supported locales
+func Locales() []string {
+	names := make([]string, 0, len(locales))
+	for locale := range locales {
+		names = append(names, locale)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsKnownLocale checks the locale is supported
+func IsKnownLocale(locale string) bool {
+	_, ok := locales[strings.ToLower(locale)]
+	return ok
+}
+
+// localeFaker reads the locale option, nil stands for the default locale
+func localeFaker(fakeConfig map[string]interface{}) (*oneFake.Faker, error) {
+	if option(fakeConfig, "locale") == nil {
+		return nil, nil
+	}
+	locale, ok := stringOption(fakeConfig, "locale")
+	if !ok || !IsKnownLocale(locale) {
+		return nil, fmt.Errorf("locale %v is not supported, use one of %s", option(fakeConfig, "locale"), strings.Join(Locales(), ", "))
+	}
+	locale = strings.ToLower(locale)
+	if locale == DefaultLocale {
+		return nil, nil
+	}
+	if lf, ok := localeFakers[locale]; ok {
+		return lf, nil
+	}
+	lf, err := oneFake.New(locale)
+	if err != nil {
+		return nil, err
+	}
+	localeFakers[locale] = lf
+	return lf, nil
+}
+
+// localeName is the full name, not every dictionary has name formats
+func localeName(lf *oneFake.Faker) string {
+	return lf.FirstName() + " " + lf.LastName()
+}
+
+// localeAddress is the full address in the order of the continental postal addresses
+func localeAddress(lf *oneFake.Faker) string {
+	return lf.StreetAddress() + ", " + lf.PostCode() + " " + lf.City()
+}
@@ -0,0 +1,56 @@
+package faker
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleGenerators(t *testing.T) {
+	for _, locale := range Locales() {
+		for _, fakeType := range []string{TypeFirstName, TypeLastName, TypeName, TypePhone, TypeAddress, TypeStreet, TypeCity, TypeZipCode} {
+			generator, err := Build(map[string]interface{}{"type": fakeType, "locale": locale})
+			if assert.NoError(t, err, locale) {
+				for i := 0; i < 20; i++ {
+					assert.NotEmpty(t, generator.GetData(), "%s %s", locale, fakeType)
+				}
+			}
+		}
+	}
+}
+
+func TestLocaleDictionaries(t *testing.T) {
+	cities := map[string][]string{
+		"fr": locales["fr"]["address.city"],
+		"es": locales["es"]["address.city"],
+	}
+	for locale, expected := range cities {
+		generator, err := Build(map[string]interface{}{"type": TypeCity, "locale": locale})
+		assert.NoError(t, err)
+		assert.Contains(t, expected, generator.GetData())
+	}
+
+	zip, err := Build(map[string]interface{}{"type": TypeZipCode, "locale": "FR"})
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^\d{5}$`), zip.GetData())
+
+	phone, err := Build(map[string]interface{}{"type": TypePhone, "locale": "es"})
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[+\d][\d ]+$`), phone.GetData())
+}
+
+func TestLocaleTemplate(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": TypeTemplate, "template": "{{city}}", "locale": "es"})
+	assert.NoError(t, err)
+	assert.Contains(t, locales["es"]["address.city"], generator.GetData())
+}
+
+func TestLocaleInvalid(t *testing.T) {
+	for _, locale := range []interface{}{"xx", 42, ""} {
+		_, err := Build(map[string]interface{}{"type": TypeCity, "locale": locale})
+		assert.Error(t, err)
+	}
+	_, err := Build(map[string]interface{}{"type": TypeCity, "locale": "it"})
+	assert.EqualError(t, err, "locale it is not supported, use one of de, en, es, fr")
+}
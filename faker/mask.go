@@ -0,0 +1,122 @@
+package faker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const defaultMaskChar = '*'
+
+// FakeMask hides the original value but KeepStart first and KeepEnd last characters
+// Separators are the characters that are neither letters nor digits, PreserveSeparators keeps them as is
+type FakeMask struct {
+	KeepStart          int
+	KeepEnd            int
+	MaskChar           rune
+	PreserveSeparators bool
+}
+
+// FakeMaskEmail masks the local part of the original email and keeps the domain
+type FakeMaskEmail struct {
+	FakeMask
+}
+
+func newFakeMask(fakeConfig map[string]interface{}) (*FakeMask, error) {
+	fm := &FakeMask{MaskChar: defaultMaskChar}
+	var ok bool
+	if option(fakeConfig, "keepStart") != nil {
+		if fm.KeepStart, ok = intOption(fakeConfig, "keepStart"); !ok || fm.KeepStart < 0 {
+			return nil, errors.New("keepStart must be a positive number")
+		}
+	}
+	if option(fakeConfig, "keepEnd") != nil {
+		if fm.KeepEnd, ok = intOption(fakeConfig, "keepEnd"); !ok || fm.KeepEnd < 0 {
+			return nil, errors.New("keepEnd must be a positive number")
+		}
+	}
+	if option(fakeConfig, "maskChar") != nil {
+		maskChar, ok := stringOption(fakeConfig, "maskChar")
+		if !ok || utf8.RuneCountInString(maskChar) != 1 {
+			return nil, errors.New("maskChar must be a single character")
+		}
+		fm.MaskChar, _ = utf8.DecodeRuneInString(maskChar)
+	}
+	if option(fakeConfig, "preserveSeparators") != nil {
+		if fm.PreserveSeparators, ok = boolOption(fakeConfig, "preserveSeparators"); !ok {
+			return nil, errors.New("preserveSeparators must be true or false")
+		}
+	}
+	return fm, nil
+}
+
+// GetData has nothing to mask
+func (fm *FakeMask) GetData() interface{} {
+	return nil
+}
+
+func (fm *FakeMask) Transform(original interface{}) interface{} {
+	if original == nil {
+		return nil
+	}
+	return fm.mask(toString(original))
+}
+
+func (fm *FakeMask) mask(value string) string {
+	runes := []rune(value)
+	maskable := 0
+	for _, r := range runes {
+		if !fm.isSeparator(r) {
+			maskable++
+		}
+	}
+	keepStart, keepEnd := fm.KeepStart, fm.KeepEnd
+	if keepStart+keepEnd >= maskable {
+		// Keeping would reveal the whole value
+		keepStart, keepEnd = 0, 0
+	}
+
+	var b strings.Builder
+	position := 0
+	for _, r := range runes {
+		if fm.isSeparator(r) {
+			b.WriteRune(r)
+			continue
+		}
+		if position < keepStart || position >= maskable-keepEnd {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(fm.MaskChar)
+		}
+		position++
+	}
+	return b.String()
+}
+
+func (fm *FakeMask) isSeparator(r rune) bool {
+	return fm.PreserveSeparators && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func (fe *FakeMaskEmail) Transform(original interface{}) interface{} {
+	if original == nil {
+		return nil
+	}
+	value := toString(original)
+	at := strings.LastIndexByte(value, '@')
+	if at < 0 {
+		return fe.mask(value)
+	}
+	return fe.mask(value[:at]) + value[at:]
+}
+
+func toString(value interface{}) string {
+	switch s := value.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	}
+	return fmt.Sprintf("%v", value)
+}
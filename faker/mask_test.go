@@ -0,0 +1,39 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeMask(t *testing.T) {
+	for _, testcase := range []struct {
+		config   map[string]interface{}
+		original interface{}
+		expected interface{}
+	}{
+		{map[string]interface{}{}, "secret", "******"},
+		{map[string]interface{}{"keepstart": 1, "keepend": 2}, "Jonathan", "J*****an"},
+		{map[string]interface{}{"keepEnd": 4, "preserveSeparators": true}, "4111-1111-1111-1234", "****-****-****-1234"},
+		{map[string]interface{}{"keepend": 4, "maskchar": "X"}, "4111-1111", "XXXXX1111"},
+		{map[string]interface{}{"keepstart": 3, "keepend": 3}, "short", "*****"},
+		{map[string]interface{}{"keepstart": 1}, "Zoë", "Z**"},
+		{map[string]interface{}{"keepend": 2}, int64(123456), "****56"},
+		{map[string]interface{}{}, nil, nil},
+	} {
+		testcase.config["type"] = "mask"
+		assert.Equal(t, testcase.expected, Fake(New(testcase.config), testcase.original), testcase.config)
+	}
+
+	assert.Nil(t, New(map[string]interface{}{"type": "mask", "maskChar": "**"}))
+	assert.Nil(t, New(map[string]interface{}{"type": "mask", "keepStart": -1}))
+}
+
+func TestFakeMaskEmail(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "mask_email", "keepStart": 1})
+	assert.Equal(t, "j*******@example.com", Fake(generator, "john.doe@example.com"))
+	assert.Equal(t, "n*******", Fake(generator, "nodomain"))
+
+	generator = New(map[string]interface{}{"type": "mask_email", "keepStart": 1, "preserveSeparators": true})
+	assert.Equal(t, "j***.***@example.com", Fake(generator, "john.doe@example.com"))
+}
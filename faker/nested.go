@@ -0,0 +1,59 @@
+package faker
+
+import (
+	"fmt"
+)
+
+// nestedGenerator is a generator of the values found under a name inside a structured value, e.g. a JSON path
+type nestedGenerator struct {
+	name      string
+	generator FakeGenerator
+}
+
+// nestedGenerators reads the list of generators of a structured value, e.g. paths of a JSON document:
+// each item has the name under the nameKey and the options of any other type, the locale defaults to the one of the parent
+func nestedGenerators(fakeConfig map[string]interface{}, listKey, nameKey string) ([]nestedGenerator, error) {
+	items, ok := option(fakeConfig, listKey).([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("%s are missing", listKey)
+	}
+
+	nested := make([]nestedGenerator, 0, len(items))
+	for i, item := range items {
+		itemConfig, ok := stringMap(item)
+		if !ok {
+			return nil, fmt.Errorf("%s %d must have a %s and a type", nameKey, i+1, nameKey)
+		}
+		name, _ := itemConfig[nameKey].(string)
+		if name == "" {
+			return nil, fmt.Errorf("%s %d must have a %s and a type", nameKey, i+1, nameKey)
+		}
+
+		generatorConfig := make(map[string]interface{}, len(itemConfig))
+		for k, v := range itemConfig {
+			if k != nameKey {
+				generatorConfig[k] = v
+			}
+		}
+		if _, ok := generatorConfig["locale"]; !ok && option(fakeConfig, "locale") != nil {
+			generatorConfig["locale"] = option(fakeConfig, "locale")
+		}
+		generator, err := Build(generatorConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %v", nameKey, name, err)
+		}
+		// The values are not known before the dump, and they are not read ahead one by one
+		if GetSampler(generator) != nil || GetBatcher(generator) != nil {
+			return nil, fmt.Errorf("%s %s: type %v can't be nested", nameKey, name, generatorConfig["type"])
+		}
+		nested = append(nested, nestedGenerator{name: name, generator: generator})
+	}
+	return nested, nil
+}
+
+// setNestedColumn names the column of the nested generators after the parent column
+func setNestedColumn(nested []nestedGenerator, column Column) {
+	for _, n := range nested {
+		SetColumn(n.generator, Column{Name: column.Name + "." + n.name})
+	}
+}
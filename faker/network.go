@@ -0,0 +1,57 @@
+package faker
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net"
+
+	anotherFake "github.com/pioz/faker"
+)
+
+// userAgentTemplates are user agents of desktop and mobile browsers,
+// %[1]d is the Chrome and Edge version, %[2]d the Firefox one, %[3]d and %[4]d the Safari major and minor ones
+var userAgentTemplates = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%[1]d.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%[1]d.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%[1]d.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%[1]d.0.0.0 Mobile Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%[1]d.0.0.0 Safari/537.36 Edg/%[1]d.0.0.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:%[2]d.0) Gecko/20100101 Firefox/%[2]d.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:%[2]d.0) Gecko/20100101 Firefox/%[2]d.0",
+	"Mozilla/5.0 (X11; Linux x86_64; rv:%[2]d.0) Gecko/20100101 Firefox/%[2]d.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/%[3]d.%[4]d Safari/605.1.15",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS %[3]d_%[4]d like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/%[3]d.%[4]d Mobile/15E148 Safari/604.1",
+}
+
+// FakeIPv6 generates global unicast IPv6 addresses
+type FakeIPv6 struct{}
+
+// FakeMAC generates locally administered unicast MAC addresses, so they never belong to a real vendor
+type FakeMAC struct{}
+
+// FakeUserAgent generates user agents of recent Chrome, Firefox, Safari and Edge versions
+type FakeUserAgent struct{}
+
+func (fi *FakeIPv6) GetData() interface{} {
+	ip := make(net.IP, net.IPv6len)
+	rand.Read(ip)
+	// 2000::/3
+	ip[0] = ip[0]&0x1f | 0x20
+	return ip.String()
+}
+
+func (fm *FakeMAC) GetData() interface{} {
+	mac := make(net.HardwareAddr, 6)
+	rand.Read(mac)
+	mac[0] = mac[0]&0xfe | 0x02
+	return mac.String()
+}
+
+func (fu *FakeUserAgent) GetData() interface{} {
+	template := userAgentTemplates[anotherFake.IntInRange(0, len(userAgentTemplates)-1)]
+	return fmt.Sprintf(template,
+		anotherFake.IntInRange(110, 130),
+		anotherFake.IntInRange(110, 130),
+		anotherFake.IntInRange(15, 17),
+		anotherFake.IntInRange(0, 6))
+}
@@ -0,0 +1,47 @@
+package faker
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeIPv6(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "ipv6"})
+	for i := 0; i < 50; i++ {
+		value := generator.GetData().(string)
+		ip := net.ParseIP(value)
+		if assert.NotNil(t, ip, value) {
+			assert.Nil(t, ip.To4(), value)
+			assert.True(t, ip.IsGlobalUnicast(), value)
+			assert.Equal(t, byte(0x20), ip[0]&0xe0, value)
+		}
+	}
+}
+
+func TestFakeMAC(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "mac"})
+	for i := 0; i < 50; i++ {
+		value := generator.GetData().(string)
+		assert.Regexp(t, `^([0-9a-f]{2}:){5}[0-9a-f]{2}$`, value)
+		mac, err := net.ParseMAC(value)
+		if assert.NoError(t, err) {
+			// Locally administered unicast
+			assert.Equal(t, byte(0x02), mac[0]&0x03, value)
+		}
+	}
+}
+
+func TestFakeUserAgent(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "user_agent"})
+	for i := 0; i < 100; i++ {
+		value := generator.GetData().(string)
+		assert.Regexp(t, `^Mozilla/5\.0 \([^()]+\) .*(Chrome|Firefox|Safari)/[\d.]+`, value)
+		assert.False(t, strings.Contains(value, "%!"), value)
+	}
+	for _, template := range userAgentTemplates {
+		assert.True(t, strings.HasPrefix(template, "Mozilla/5.0 ("), template)
+	}
+}
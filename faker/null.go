@@ -0,0 +1,53 @@
+package faker
+
+import (
+	"errors"
+)
+
+// FakeNull replaces the values with NULL, the column must be nullable
+type FakeNull struct{}
+
+// FakeDefault replaces the values with the column default, NULL if the column has no default and is nullable
+// A default expression like CURRENT_TIMESTAMP is left to the server that loads the dump
+type FakeDefault struct {
+	column Column
+}
+
+// DefaultKeyword stands for the default expression of the column, it is written as DEFAULT
+type DefaultKeyword struct{}
+
+func (fn *FakeNull) GetData() interface{} {
+	return nil
+}
+
+func (fn *FakeNull) CheckColumn(column Column) error {
+	if !column.Nullable {
+		return errors.New("the column is NOT NULL, use default or another type")
+	}
+	return nil
+}
+
+func (fd *FakeDefault) SetColumn(column Column) {
+	fd.column = column
+}
+
+func (fd *FakeDefault) CheckColumn(column Column) error {
+	if column.Default == nil && column.DefaultExpr == "" && !column.Nullable {
+		return errors.New("the column is NOT NULL without a default, use another type")
+	}
+	return nil
+}
+
+func (fd *FakeDefault) GetData() interface{} {
+	if fd.column.DefaultExpr != "" {
+		return DefaultKeyword{}
+	}
+	if fd.column.Default == nil {
+		return nil
+	}
+	// Numbers are written as they are, like the dump of the original values
+	if columnTypeKind(fd.column.Type) != "" {
+		return Decimal(*fd.column.Default)
+	}
+	return *fd.column.Default
+}
@@ -0,0 +1,46 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeNull(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "null"})
+	assert.Nil(t, generator.GetData())
+	assert.NoError(t, CheckColumn(generator, Column{Name: "token", Nullable: true}))
+	assert.EqualError(t, CheckColumn(generator, Column{Name: "token"}), "the column is NOT NULL, use default or another type")
+}
+
+func TestUnquotedNullType(t *testing.T) {
+	_, err := Build(map[string]interface{}{"type": nil})
+	assert.EqualError(t, err, "type is missing, quote \"null\" to write NULL")
+}
+
+func TestFakeDefault(t *testing.T) {
+	value := "new"
+	generator := New(map[string]interface{}{"type": "default"})
+	SetColumn(generator, Column{Name: "status", Type: "varchar(16)", Default: &value})
+	assert.Equal(t, "new", generator.GetData())
+
+	score := "0.50"
+	SetColumn(generator, Column{Name: "score", Type: "decimal(4,2)", Default: &score})
+	assert.Equal(t, Decimal("0.50"), generator.GetData())
+
+	SetColumn(generator, Column{Name: "note", Type: "text", Nullable: true})
+	assert.Nil(t, generator.GetData())
+
+	assert.NoError(t, CheckColumn(generator, Column{Name: "note", Nullable: true}))
+	assert.EqualError(t, CheckColumn(generator, Column{Name: "note"}), "the column is NOT NULL without a default, use another type")
+	// The server that loads the dump evaluates the default expression
+	created := Column{Name: "created_at", Type: "timestamp", DefaultExpr: "now()"}
+	assert.NoError(t, CheckColumn(generator, created))
+	SetColumn(generator, created)
+	assert.Equal(t, DefaultKeyword{}, generator.GetData())
+}
+
+func TestCheckColumnWrapped(t *testing.T) {
+	generator := NewLengthLimiter(&FakeNull{}, 10)
+	assert.Error(t, CheckColumn(generator, Column{Name: "token"}))
+}
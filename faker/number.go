@@ -0,0 +1,219 @@
+package faker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	anotherFake "github.com/pioz/faker"
+)
+
+const (
+	roundModeRound = "round"
+	roundModeFloor = "floor"
+)
+
+// Decimal is an exact number, written into the dump as is
+type Decimal string
+
+// FakeNumber is a random number in the range
+type FakeNumber struct {
+	Min    float64
+	Max    float64
+	column Column
+}
+
+// FakeNoise moves the original number by up to Multiplicative share of it and by up to Additive
+type FakeNoise struct {
+	Multiplicative float64
+	Additive       float64
+	column         Column
+}
+
+// FakeRound rounds the original number to a multiple of Step, Floor puts it into the band starting at the multiple
+type FakeRound struct {
+	Step   float64
+	Floor  bool
+	column Column
+}
+
+var (
+	integerTypeRe = regexp.MustCompile(`^(tiny|small|medium|big)?int(eger)?\b|^(small|big)?serial\b|^year\b`)
+	decimalTypeRe = regexp.MustCompile(`^(decimal|numeric|dec|fixed)\b(\(\s*\d+\s*(,\s*(\d+)\s*)?\))?`)
+	floatTypeRe   = regexp.MustCompile(`^(float|double|real)\b`)
+)
+
+func newFakeNumber(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	min, minOk := floatOption(fakeConfig, "min")
+	max, maxOk := floatOption(fakeConfig, "max")
+	if !minOk || !maxOk {
+		return nil, errors.New("min and max must be numbers")
+	}
+	if max < min {
+		return nil, errors.New("max must not be less than min")
+	}
+	return &FakeNumber{Min: min, Max: max}, nil
+}
+
+func newFakeNoise(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	multiplicative, _ := floatOption(fakeConfig, "multiplicative")
+	additive, _ := floatOption(fakeConfig, "additive")
+	if multiplicative < 0 || additive < 0 || multiplicative == 0 && additive == 0 {
+		return nil, errors.New("multiplicative or additive must be a positive number")
+	}
+	return &FakeNoise{Multiplicative: multiplicative, Additive: additive}, nil
+}
+
+func newFakeRound(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	step, ok := floatOption(fakeConfig, "step")
+	if !ok || step <= 0 {
+		return nil, errors.New("step must be a positive number")
+	}
+	mode, _ := stringOption(fakeConfig, "mode")
+	if mode != "" && mode != roundModeRound && mode != roundModeFloor {
+		return nil, fmt.Errorf("mode must be %s or %s", roundModeRound, roundModeFloor)
+	}
+	return &FakeRound{Step: step, Floor: mode == roundModeFloor}, nil
+}
+
+func (fn *FakeNumber) SetColumn(column Column) {
+	fn.column = column
+}
+
+func (fn *FakeNumber) GetData() interface{} {
+	kind := columnTypeKind(fn.column.Type)
+	// Whole bounds of an unknown column mean integers
+	if kind == "integer" || kind == "" && fn.Min == math.Trunc(fn.Min) && fn.Max == math.Trunc(fn.Max) {
+		if min, max := math.Ceil(fn.Min), math.Floor(fn.Max); min <= max {
+			// Rounding a float in the range would give the bounds half the chance of the other integers
+			value := anotherFake.Int64InRange(int64(min), int64(max)+1)
+			return formatNumber(float64(value), fn.column, value)
+		}
+	}
+	return formatNumber(anotherFake.Float64InRange(fn.Min, fn.Max), fn.column, nil)
+}
+
+func (fn *FakeNoise) SetColumn(column Column) {
+	fn.column = column
+}
+
+// GetData has no number to add noise to
+func (fn *FakeNoise) GetData() interface{} {
+	return nil
+}
+
+func (fn *FakeNoise) Transform(original interface{}) interface{} {
+	value, ok := parseNumber(original)
+	if !ok {
+		return original
+	}
+	value *= 1 + anotherFake.Float64InRange(-fn.Multiplicative, fn.Multiplicative)
+	value += anotherFake.Float64InRange(-fn.Additive, fn.Additive)
+	return formatNumber(value, fn.column, original)
+}
+
+func (fr *FakeRound) SetColumn(column Column) {
+	fr.column = column
+}
+
+// GetData has no number to round
+func (fr *FakeRound) GetData() interface{} {
+	return nil
+}
+
+func (fr *FakeRound) Transform(original interface{}) interface{} {
+	value, ok := parseNumber(original)
+	if !ok {
+		return original
+	}
+	if fr.Floor {
+		value = math.Floor(value/fr.Step) * fr.Step
+	} else {
+		value = math.Round(value/fr.Step) * fr.Step
+	}
+	return formatNumber(value, fr.column, original)
+}
+
+// parseNumber reads the original value, NULL and non-numbers are not parsed
+func parseNumber(original interface{}) (float64, bool) {
+	switch value := original.(type) {
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	case string:
+		f, err := strconv.ParseFloat(value, 64)
+		return f, err == nil
+	case Decimal:
+		f, err := strconv.ParseFloat(string(value), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// columnTypeKind tells whether the column holds integer, decimal or float numbers, empty if unknown
+func columnTypeKind(columnType string) string {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	switch {
+	case integerTypeRe.MatchString(columnType):
+		return "integer"
+	case decimalTypeRe.MatchString(columnType):
+		return "decimal"
+	case floatTypeRe.MatchString(columnType):
+		return "float"
+	}
+	return ""
+}
+
+// formatNumber converts the number to the column type, the original value stands for the type when the column is unknown
+func formatNumber(value float64, column Column, original interface{}) interface{} {
+	columnType := strings.ToLower(column.Type)
+	if strings.Contains(columnType, "unsigned") && value < 0 {
+		value = 0
+	}
+
+	switch columnTypeKind(columnType) {
+	case "integer":
+		return int64(math.Round(value))
+	case "decimal":
+		scale := -1
+		if m := decimalTypeRe.FindStringSubmatch(columnType); m != nil && m[2] != "" {
+			// DECIMAL(M) has no fractional part
+			scale, _ = strconv.Atoi(m[4])
+		}
+		if scale < 0 {
+			scale = originalScale(original)
+		}
+		return Decimal(strconv.FormatFloat(value, 'f', scale, 64))
+	case "float":
+		return value
+	}
+
+	switch original.(type) {
+	case int64:
+		return int64(math.Round(value))
+	case string, Decimal:
+		return Decimal(strconv.FormatFloat(value, 'f', originalScale(original), 64))
+	}
+	return value
+}
+
+// originalScale counts the fractional digits of the original number, 2 if it is not known
+func originalScale(original interface{}) int {
+	var s string
+	switch value := original.(type) {
+	case string:
+		s = value
+	case Decimal:
+		s = string(value)
+	default:
+		return 2
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatNumber(t *testing.T) {
+	for _, testcase := range []struct {
+		columnType string
+		original   interface{}
+		expected   interface{}
+	}{
+		{"int(11)", nil, int64(1235)},
+		{"bigint unsigned", nil, int64(1235)},
+		{"decimal(10,2)", nil, Decimal("1234.57")},
+		{"decimal(10)", nil, Decimal("1235")},
+		{"numeric", "1.5000", Decimal("1234.5670")},
+		{"double", nil, 1234.567},
+		{"", int64(1), int64(1235)},
+		{"", "10.5", Decimal("1234.6")},
+		{"", 2.5, 1234.567},
+	} {
+		assert.Equal(t, testcase.expected, formatNumber(1234.567, Column{Type: testcase.columnType}, testcase.original), testcase.columnType)
+	}
+	assert.Equal(t, int64(0), formatNumber(-3, Column{Type: "int(10) unsigned"}, nil))
+}
+
+func TestFakeNumberInRange(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "number", "min": 18, "max": 65})
+	for i := 0; i < 100; i++ {
+		value := generator.GetData().(int64)
+		assert.True(t, value >= 18 && value <= 65, value)
+	}
+
+	generator = New(map[string]interface{}{"type": "number", "min": 0, "max": "1000.5"})
+	SetColumn(generator, Column{Type: "decimal(8,2)"})
+	assert.Regexp(t, `^\d+\.\d\d$`, generator.GetData())
+
+	assert.Nil(t, New(map[string]interface{}{"type": "number", "min": 10, "max": 1}))
+}
+
+func TestFakeNumberIntegersAreUniform(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "number", "min": 1, "max": 3})
+	SetColumn(generator, Column{Type: "int(11)"})
+	counts := map[int64]int{}
+	for i := 0; i < 3000; i++ {
+		counts[generator.GetData().(int64)]++
+	}
+	assert.Len(t, counts, 3)
+	for value, count := range counts {
+		assert.InDelta(t, 1000, count, 200, value)
+	}
+
+	generator = New(map[string]interface{}{"type": "number", "min": "0.5", "max": "2.5"})
+	SetColumn(generator, Column{Type: "int(11)"})
+	for i := 0; i < 100; i++ {
+		value := generator.GetData().(int64)
+		assert.True(t, value >= 1 && value <= 2, value)
+	}
+}
+
+func TestFakeNoise(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "noise", "multiplicative": 0.1, "additive": 5})
+	SetColumn(generator, Column{Type: "decimal(12,2)"})
+	for i := 0; i < 100; i++ {
+		value, ok := parseNumber(Fake(generator, "1000.00"))
+		assert.True(t, ok)
+		assert.True(t, value >= 895 && value <= 1105, value)
+	}
+	assert.Nil(t, Fake(generator, nil))
+	assert.Nil(t, New(map[string]interface{}{"type": "noise"}))
+}
+
+func TestFakeRound(t *testing.T) {
+	bands := New(map[string]interface{}{"type": "round", "step": 5, "mode": "floor"})
+	SetColumn(bands, Column{Type: "tinyint(3) unsigned"})
+	assert.Equal(t, int64(35), Fake(bands, int64(39)))
+	assert.Equal(t, int64(40), Fake(bands, int64(40)))
+
+	rounded := New(map[string]interface{}{"type": "round", "step": 1000})
+	assert.Equal(t, Decimal("54000.00"), Fake(rounded, "53678.25"))
+	assert.Equal(t, 3000.0, Fake(rounded, 2500.0))
+
+	assert.Nil(t, New(map[string]interface{}{"type": "round", "step": 5, "mode": "ceil"}))
+}
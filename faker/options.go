@@ -0,0 +1,90 @@
+package faker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// option looks the option up, viper lowercases camelCase keys of the config file
+func option(fakeConfig map[string]interface{}, name string) interface{} {
+	if value, ok := fakeConfig[name]; ok {
+		return value
+	}
+	return fakeConfig[strings.ToLower(name)]
+}
+
+// intOption reads an integer option, YAML could give it as a number or a string
+func intOption(fakeConfig map[string]interface{}, name string) (int, bool) {
+	switch value := option(fakeConfig, name).(type) {
+	case int:
+		return value, true
+	case int64:
+		return int(value), true
+	case float64:
+		return int(value), value == float64(int(value))
+	case string:
+		i, err := strconv.Atoi(value)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// floatOption reads a number option
+func floatOption(fakeConfig map[string]interface{}, name string) (float64, bool) {
+	switch value := option(fakeConfig, name).(type) {
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	case string:
+		f, err := strconv.ParseFloat(value, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func stringOption(fakeConfig map[string]interface{}, name string) (string, bool) {
+	value, ok := option(fakeConfig, name).(string)
+	return value, ok
+}
+
+func boolOption(fakeConfig map[string]interface{}, name string) (bool, bool) {
+	switch value := option(fakeConfig, name).(type) {
+	case bool:
+		return value, true
+	case string:
+		b, err := strconv.ParseBool(value)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// stringMap reads a nested map, YAML decoders give maps in lists either with string or with interface{} keys
+func stringMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			result[fmt.Sprintf("%v", k)] = v
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// timeOption reads a date or a date and time option
+func timeOption(fakeConfig map[string]interface{}, name string) (time.Time, bool) {
+	switch value := option(fakeConfig, name).(type) {
+	case time.Time:
+		return value, true
+	case string:
+		return parseTime(value)
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,165 @@
+package faker
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	passwordAlgorithmBcrypt   = "bcrypt"
+	passwordAlgorithmArgon2ID = "argon2id"
+	// passwordAlgorithmPHP is bcrypt with the $2y$ prefix written by PHP password_hash
+	passwordAlgorithmPHP = "php"
+
+	// Defaults of PHP password_hash
+	defaultArgon2Memory  = 65536
+	defaultArgon2Time    = 4
+	defaultArgon2Threads = 1
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+	// bcrypt ignores the bytes after the 72nd
+	bcryptMaxPassword = 72
+)
+
+// argon2Encoding is the unpadded base64 of the PHC string format used by PHP and libargon2
+var argon2Encoding = base64.RawStdEncoding
+
+// FakePasswordHash replaces the values with a hash of Password, so any account could be logged into with it
+// The hash is computed once as bcrypt and argon2id are slow on purpose
+type FakePasswordHash struct {
+	Password  string
+	Algorithm string
+	// Cost is the bcrypt cost
+	Cost int
+	// Memory in KiB, Time and Threads are the argon2id parameters
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+
+	once sync.Once
+	hash string
+	err  error
+}
+
+func newFakePasswordHash(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	password, ok := stringOption(fakeConfig, "password")
+	if !ok || password == "" {
+		return nil, errors.New("password is missing")
+	}
+	algorithm, ok := stringOption(fakeConfig, "algorithm")
+	if !ok {
+		algorithm = passwordAlgorithmBcrypt
+	}
+	generator := &FakePasswordHash{Password: password, Algorithm: algorithm}
+
+	switch algorithm {
+	case passwordAlgorithmBcrypt, passwordAlgorithmPHP:
+		cost, ok := intOption(fakeConfig, "cost")
+		if !ok {
+			cost = bcrypt.DefaultCost
+		}
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		if len(password) > bcryptMaxPassword {
+			return nil, fmt.Errorf("password must not be longer than %d bytes", bcryptMaxPassword)
+		}
+		generator.Cost = cost
+	case passwordAlgorithmArgon2ID:
+		memory, ok := intOption(fakeConfig, "memory")
+		if !ok {
+			memory = defaultArgon2Memory
+		}
+		time, ok := intOption(fakeConfig, "time")
+		if !ok {
+			time = defaultArgon2Time
+		}
+		threads, ok := intOption(fakeConfig, "threads")
+		if !ok {
+			threads = defaultArgon2Threads
+		}
+		switch {
+		case threads < 1 || threads > 255:
+			return nil, errors.New("threads must be between 1 and 255")
+		case time < 1:
+			return nil, errors.New("time must be a positive number")
+		case memory < 8*threads || int64(memory) > math.MaxUint32:
+			return nil, fmt.Errorf("memory must be between %d and %d KiB", 8*threads, uint32(math.MaxUint32))
+		}
+		generator.Memory = uint32(memory)
+		generator.Time = uint32(time)
+		generator.Threads = uint8(threads)
+	default:
+		return nil, fmt.Errorf("algorithm must be %s, %s or %s", passwordAlgorithmBcrypt, passwordAlgorithmArgon2ID, passwordAlgorithmPHP)
+	}
+	return generator, nil
+}
+
+func (fp *FakePasswordHash) GetData() interface{} {
+	hash, err := fp.compute()
+	if err != nil {
+		return nil
+	}
+	return hash
+}
+
+// Transform keeps NULL, accounts without a password stay without it
+func (fp *FakePasswordHash) Transform(original interface{}) interface{} {
+	if original == nil {
+		return nil
+	}
+	return fp.GetData()
+}
+
+func (fp *FakePasswordHash) Err() error {
+	return fp.err
+}
+
+// CheckColumn makes sure the hash is not cut by the column length
+func (fp *FakePasswordHash) CheckColumn(column Column) error {
+	hash, err := fp.compute()
+	if err != nil {
+		return err
+	}
+	if column.MaxLength > 0 && len(hash) > column.MaxLength {
+		return fmt.Errorf("the hash is %d characters long, the column fits %d", len(hash), column.MaxLength)
+	}
+	return nil
+}
+
+func (fp *FakePasswordHash) compute() (string, error) {
+	fp.once.Do(func() {
+		switch fp.Algorithm {
+		case passwordAlgorithmArgon2ID:
+			fp.hash, fp.err = fp.argon2ID()
+		default:
+			var hash []byte
+			hash, fp.err = bcrypt.GenerateFromPassword([]byte(fp.Password), fp.Cost)
+			fp.hash = string(hash)
+			if fp.err == nil && fp.Algorithm == passwordAlgorithmPHP {
+				// PHP verifies both prefixes but writes $2y$
+				fp.hash = "$2y$" + fp.hash[len("$2a$"):]
+			}
+		}
+	})
+	return fp.hash, fp.err
+}
+
+// argon2ID encodes the hash like PHP password_hash with PASSWORD_ARGON2ID
+func (fp *FakePasswordHash) argon2ID() (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(fp.Password), salt, fp.Time, fp.Memory, fp.Threads, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, fp.Memory, fp.Time, fp.Threads,
+		argon2Encoding.EncodeToString(salt), argon2Encoding.EncodeToString(key)), nil
+}
@@ -0,0 +1,79 @@
+package faker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestFakePasswordHashBcrypt(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "password_hash", "password": "qa-secret", "cost": 4})
+	hash, ok := Fake(generator, "$2y$10$original").(string)
+	assert.True(t, ok)
+	assert.Regexp(t, `^\$2a\$04\$[./A-Za-z0-9]{53}$`, hash)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("qa-secret")))
+	// The hash is computed once
+	assert.Equal(t, hash, Fake(generator, "$2y$10$another"))
+	assert.Equal(t, hash, generator.GetData())
+	assert.Nil(t, Fake(generator, nil))
+
+	php := New(map[string]interface{}{"type": "password_hash", "password": "qa-secret", "algorithm": "php", "cost": 4})
+	hash = php.GetData().(string)
+	assert.True(t, strings.HasPrefix(hash, "$2y$04$"), hash)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("qa-secret")))
+}
+
+func TestFakePasswordHashArgon2ID(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "password_hash", "password": "qa-secret", "algorithm": "argon2id",
+		"memory": 1024, "time": 2, "threads": 2})
+	hash := generator.GetData().(string)
+	assert.Equal(t, hash, generator.GetData())
+
+	var memory, time uint32
+	var threads uint8
+	parts := strings.Split(hash, "$")
+	if !assert.Len(t, parts, 6, hash) {
+		return
+	}
+	assert.Equal(t, "argon2id", parts[1])
+	assert.Equal(t, "v=19", parts[2])
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{uint32(1024), uint32(2), uint8(2)}, []interface{}{memory, time, threads})
+
+	salt, err := argon2Encoding.DecodeString(parts[4])
+	assert.NoError(t, err)
+	key, err := argon2Encoding.DecodeString(parts[5])
+	assert.NoError(t, err)
+	assert.Equal(t, key, argon2.IDKey([]byte("qa-secret"), salt, time, memory, threads, uint32(len(key))))
+}
+
+func TestFakePasswordHashCheckColumn(t *testing.T) {
+	generator := New(map[string]interface{}{"type": "password_hash", "password": "qa-secret", "cost": 4})
+	assert.NoError(t, CheckColumn(generator, Column{Name: "password", MaxLength: 60}))
+	assert.NoError(t, CheckColumn(generator, Column{Name: "password"}))
+	assert.EqualError(t, CheckColumn(generator, Column{Name: "password", MaxLength: 32}), "the hash is 60 characters long, the column fits 32")
+}
+
+func TestFakePasswordHashErrors(t *testing.T) {
+	for _, testcase := range []struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{}, "password is missing"},
+		{map[string]interface{}{"password": "x", "algorithm": "md5"}, "algorithm must be bcrypt, argon2id or php"},
+		{map[string]interface{}{"password": "x", "cost": 3}, "cost must be between 4 and 31"},
+		{map[string]interface{}{"password": strings.Repeat("x", 73)}, "password must not be longer than 72 bytes"},
+		{map[string]interface{}{"password": "x", "algorithm": "argon2id", "threads": 0}, "threads must be between 1 and 255"},
+		{map[string]interface{}{"password": "x", "algorithm": "argon2id", "time": 0}, "time must be a positive number"},
+		{map[string]interface{}{"password": "x", "algorithm": "argon2id", "memory": 4}, "memory must be between 8 and 4294967295 KiB"},
+	} {
+		testcase.config["type"] = "password_hash"
+		_, err := Build(testcase.config)
+		assert.EqualError(t, err, testcase.expected, testcase.config)
+	}
+}
@@ -0,0 +1,141 @@
+package faker
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+
+	anotherFake "github.com/pioz/faker"
+)
+
+// Unbounded repetitions like * and + repeat up to this many times over the minimum
+const maxPatternRepeat = 10
+
+// printable ASCII is preferred for classes like \D or [^0-9] and for the dot
+var printableASCII = []rune{' ', '~'}
+
+// FakePattern generates strings matching a regular expression
+type FakePattern struct {
+	Pattern string
+	re      *syntax.Regexp
+}
+
+func newFakePattern(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	pattern, ok := stringOption(fakeConfig, "pattern")
+	if !ok || pattern == "" {
+		return nil, errors.New("pattern is missing")
+	}
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("pattern is invalid: %v", err)
+	}
+	re = re.Simplify()
+	if err := checkPattern(re); err != nil {
+		return nil, err
+	}
+	return &FakePattern{Pattern: pattern, re: re}, nil
+}
+
+// checkPattern rejects what can't be generated
+func checkPattern(re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return errors.New("pattern matches nothing")
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return errors.New("pattern has an empty character class")
+		}
+	}
+	for _, sub := range re.Sub {
+		if err := checkPattern(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fp *FakePattern) GetData() interface{} {
+	var b strings.Builder
+	generatePattern(&b, fp.re)
+	return b.String()
+}
+
+func generatePattern(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && anotherFake.Bool() {
+				r = unicode.SimpleFold(r)
+			}
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(randomRune(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		b.WriteRune(randomRune(printableASCII))
+	case syntax.OpCapture:
+		generatePattern(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			generatePattern(b, sub)
+		}
+	case syntax.OpAlternate:
+		generatePattern(b, re.Sub[anotherFake.IntInRange(0, len(re.Sub)-1)])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			min, max = 0, -1
+		case syntax.OpPlus:
+			min, max = 1, -1
+		case syntax.OpQuest:
+			min, max = 0, 1
+		}
+		if max < 0 {
+			max = min + maxPatternRepeat
+		}
+		for i := anotherFake.IntInRange(min, max); i > 0; i-- {
+			generatePattern(b, re.Sub[0])
+		}
+	}
+	// Anchors and boundaries produce nothing
+}
+
+// randomRune picks a rune from the ranges, printable ASCII ones first
+func randomRune(ranges []rune) rune {
+	if ascii := intersectRanges(ranges, printableASCII); len(ascii) > 0 {
+		ranges = ascii
+	}
+	total := 0
+	for i := 0; i < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	n := anotherFake.IntInRange(0, total-1)
+	for i := 0; i < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return ranges[0]
+}
+
+func intersectRanges(ranges, bounds []rune) []rune {
+	var result []rune
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < bounds[0] {
+			lo = bounds[0]
+		}
+		if hi > bounds[1] {
+			hi = bounds[1]
+		}
+		if lo <= hi {
+			result = append(result, lo, hi)
+		}
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package faker
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakePatternMatches(t *testing.T) {
+	for _, pattern := range []string{
+		`[A-Z]{2}-\d{4}`,
+		`^(DE|FR)\d{2} ?[0-9a-f]{8}$`,
+		`[^0-9]{3}\w+\.?`,
+		`(?i)abc.x*`,
+	} {
+		generator, err := Build(map[string]interface{}{"type": "pattern", "pattern": pattern})
+		assert.NoError(t, err, pattern)
+		re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+		for i := 0; i < 50; i++ {
+			value := generator.GetData().(string)
+			assert.Regexp(t, re, value, pattern)
+		}
+	}
+}
+
+func TestFakePatternInvalid(t *testing.T) {
+	_, err := Build(map[string]interface{}{"type": "pattern"})
+	assert.EqualError(t, err, "pattern is missing")
+	_, err = Build(map[string]interface{}{"type": "pattern", "pattern": "[a-"})
+	assert.EqualError(t, err, "pattern is invalid: error parsing regexp: missing closing ]: `[a-`")
+	_, err = Build(map[string]interface{}{"type": "pattern", "pattern": `[^\x00-\x{10FFFF}]`})
+	assert.Error(t, err)
+}
+
+func TestFakeTemplate(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "template", "template": "{{first_name | lower}}.{{ last_name|lower }}@example.test"})
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[^A-Z{}]+\.[^A-Z{}]+@example\.test$`, generator.GetData())
+
+	for template, expected := range map[string]string{
+		"":                    "template is missing",
+		"{{nickname}}":        "placeholder {{nickname}}: unknown type nickname",
+		"{{date_jitter}}":     "placeholder {{date_jitter}}: days must be a positive number",
+		"{{mask}}":            "placeholder {{mask}}: type needs the original value",
+		"{{null}}":            "placeholder {{null}}: NULL has no text, leave the placeholder out",
+		"{{default}}":         "placeholder {{default}}: type needs the column, the template has none",
+		"ID-{{fixed}}":        "placeholder {{fixed}}: type has no value, write the text into the template",
+		"{{name | title}}":    "placeholder {{name}}: unknown filter title",
+		"{{name}} {{ Name }}": "template has a malformed placeholder",
+		"{{template}}":        "placeholder {{template}}: template is missing",
+	} {
+		_, err := Build(map[string]interface{}{"type": "template", "template": template})
+		assert.EqualError(t, err, expected, template)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	_, err := Build(map[string]interface{}{})
+	assert.EqualError(t, err, "type is missing")
+	_, err = Build(map[string]interface{}{"type": "nickname"})
+	assert.EqualError(t, err, "unknown type nickname")
+	_, err = Build(map[string]interface{}{"type": "string", "length": "ten"})
+	assert.EqualError(t, err, "length must be a positive number")
+	assert.Nil(t, New(map[string]interface{}{"type": "nickname"}))
+}
@@ -0,0 +1,187 @@
+package faker
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	anotherFake "github.com/pioz/faker"
+)
+
+// cardRange is a range of card number prefixes of a brand
+type cardRange struct {
+	from, to int
+	length   int
+}
+
+// cardBrands are the IIN ranges of the card brands
+var cardBrands = map[string][]cardRange{
+	"visa":       {{4, 4, 16}},
+	"mastercard": {{51, 55, 16}, {2221, 2720, 16}},
+	"amex":       {{34, 34, 15}, {37, 37, 15}},
+	"discover":   {{6011, 6011, 16}, {65, 65, 16}},
+	"jcb":        {{3528, 3589, 16}},
+	"diners":     {{36, 36, 14}},
+}
+
+// ibanFormats are the BBAN formats of the countries: n is a digit, a is an uppercase letter, c is either
+var ibanFormats = map[string]string{
+	"AT": "16n",
+	"BE": "12n",
+	"CH": "5n12c",
+	"DE": "18n",
+	"DK": "14n",
+	"ES": "20n",
+	"FI": "14n",
+	"FR": "10n11c2n",
+	"GB": "4a14n",
+	"IE": "4a14n",
+	"IT": "1a10n12c",
+	"LU": "3n13c",
+	"NL": "4a10n",
+	"NO": "11n",
+	"PL": "24n",
+	"PT": "21n",
+	"SE": "20n",
+}
+
+// cardBrandNames and ibanCountries are the sorted keys of cardBrands and ibanFormats
+var cardBrandNames, ibanCountries []string
+
+func init() {
+	for brand := range cardBrands {
+		cardBrandNames = append(cardBrandNames, brand)
+	}
+	sort.Strings(cardBrandNames)
+	for country := range ibanFormats {
+		ibanCountries = append(ibanCountries, country)
+	}
+	sort.Strings(ibanCountries)
+}
+
+// FakeCreditCard generates Luhn valid card numbers of Brand, of any known brand if it is empty
+type FakeCreditCard struct {
+	Brand string
+}
+
+// FakeIBAN generates IBANs with valid check digits of Country, of any known country if it is empty
+type FakeIBAN struct {
+	Country string
+}
+
+func newFakeCreditCard(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	brand, _ := stringOption(fakeConfig, "brand")
+	brand = strings.ToLower(brand)
+	if _, ok := cardBrands[brand]; brand != "" && !ok {
+		return nil, fmt.Errorf("brand must be one of %s", strings.Join(cardBrandNames, ", "))
+	}
+	return &FakeCreditCard{Brand: brand}, nil
+}
+
+func newFakeIBAN(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	country, _ := stringOption(fakeConfig, "country")
+	country = strings.ToUpper(country)
+	if _, ok := ibanFormats[country]; country != "" && !ok {
+		return nil, fmt.Errorf("country must be one of %s", strings.Join(ibanCountries, ", "))
+	}
+	return &FakeIBAN{Country: country}, nil
+}
+
+func (fc *FakeCreditCard) GetData() interface{} {
+	brand := fc.Brand
+	if brand == "" {
+		brand = cardBrandNames[anotherFake.IntInRange(0, len(cardBrandNames)-1)]
+	}
+	ranges := cardBrands[brand]
+	r := ranges[anotherFake.IntInRange(0, len(ranges)-1)]
+
+	number := []byte(strconv.Itoa(anotherFake.IntInRange(r.from, r.to)))
+	for len(number) < r.length-1 {
+		number = append(number, randomDigit())
+	}
+	return string(append(number, luhnDigit(number)))
+}
+
+func (fi *FakeIBAN) GetData() interface{} {
+	country := fi.Country
+	if country == "" {
+		country = ibanCountries[anotherFake.IntInRange(0, len(ibanCountries)-1)]
+	}
+	bban := generateBBAN(ibanFormats[country])
+	return country + ibanCheckDigits(country, bban) + bban
+}
+
+// luhnDigit computes the check digit appended to the digits
+func luhnDigit(digits []byte) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		// The digits are doubled starting from the one next to the check digit
+		if (len(digits)-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// generateBBAN fills a format like 4a14n
+func generateBBAN(format string) string {
+	var b strings.Builder
+	count := 0
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c >= '0' && c <= '9' {
+			count = count*10 + int(c-'0')
+			continue
+		}
+		for ; count > 0; count-- {
+			switch c {
+			case 'n':
+				b.WriteByte(randomDigit())
+			case 'a':
+				b.WriteByte(randomLetter())
+			default:
+				if anotherFake.IntInRange(0, 35) < 10 {
+					b.WriteByte(randomDigit())
+				} else {
+					b.WriteByte(randomLetter())
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// ibanCheckDigits computes the ISO 7064 MOD 97-10 check digits of the IBAN
+func ibanCheckDigits(country, bban string) string {
+	remainder := ibanRemainder(bban + country + "00")
+	return fmt.Sprintf("%02d", 98-remainder)
+}
+
+// ibanRemainder is the remainder of the division by 97 of the number the letters of the string are replaced in by 10 to 35
+func ibanRemainder(s string) int {
+	var digits strings.Builder
+	for _, c := range s {
+		if c >= 'A' && c <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(c-'A') + 10))
+		} else {
+			digits.WriteRune(c)
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	return int(new(big.Int).Mod(n, big.NewInt(97)).Int64())
+}
+
+func randomDigit() byte {
+	return byte('0' + anotherFake.IntInRange(0, 9))
+}
+
+func randomLetter() byte {
+	return byte('A' + anotherFake.IntInRange(0, 25))
+}
@@ -0,0 +1,94 @@
+package faker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// luhnValid tells whether the last digit of the number is its Luhn check digit
+func luhnValid(number string) bool {
+	sum := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if (len(number)-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// ibanValid tells whether the check digits of the IBAN are valid
+func ibanValid(iban string) bool {
+	return ibanRemainder(iban[4:]+iban[:4]) == 1
+}
+
+func TestLuhnDigit(t *testing.T) {
+	// Well-known test card numbers
+	for _, number := range []string{"4111111111111111", "5555555555554444", "378282246310005", "6011111111111117", "30569309025904"} {
+		assert.Equal(t, number[len(number)-1], luhnDigit([]byte(number[:len(number)-1])), number)
+		assert.True(t, luhnValid(number), number)
+	}
+	assert.False(t, luhnValid("4111111111111112"))
+}
+
+func TestFakeCreditCard(t *testing.T) {
+	for brand, pattern := range map[string]string{
+		"visa":       `^4\d{15}$`,
+		"mastercard": `^(5[1-5]\d{14}|2(22[1-9]|2[3-9]\d|[3-6]\d\d|7[01]\d|720)\d{12})$`,
+		"amex":       `^3[47]\d{13}$`,
+		"discover":   `^(6011|65\d\d)\d{12}$`,
+		"jcb":        `^35(2[89]|[3-8]\d)\d{12}$`,
+		"diners":     `^36\d{12}$`,
+	} {
+		generator := New(map[string]interface{}{"type": "credit_card", "brand": brand})
+		for i := 0; i < 50; i++ {
+			number := generator.GetData().(string)
+			assert.Regexp(t, pattern, number, brand)
+			assert.True(t, luhnValid(number), number)
+		}
+	}
+
+	generator := New(map[string]interface{}{"type": "credit_card"})
+	for i := 0; i < 50; i++ {
+		assert.True(t, luhnValid(generator.GetData().(string)))
+	}
+	_, err := Build(map[string]interface{}{"type": "credit_card", "brand": "maestro"})
+	assert.EqualError(t, err, "brand must be one of amex, diners, discover, jcb, mastercard, visa")
+}
+
+func TestIBANCheckDigits(t *testing.T) {
+	for _, iban := range []string{"DE89370400440532013000", "GB29NWBK60161331926819", "FR1420041010050500013M02606", "BE68539007547034"} {
+		assert.Equal(t, iban[2:4], ibanCheckDigits(iban[:2], iban[4:]), iban)
+		assert.True(t, ibanValid(iban), iban)
+	}
+	assert.False(t, ibanValid("DE88370400440532013000"))
+}
+
+func TestFakeIBAN(t *testing.T) {
+	lengths := map[string]int{"DE": 22, "FR": 27, "GB": 22, "IT": 27, "NL": 18, "CH": 21, "NO": 15, "PL": 28}
+	for country, length := range lengths {
+		generator := New(map[string]interface{}{"type": "iban", "country": strings.ToLower(country)})
+		for i := 0; i < 20; i++ {
+			iban := generator.GetData().(string)
+			assert.Len(t, iban, length, iban)
+			assert.True(t, strings.HasPrefix(iban, country), iban)
+			assert.True(t, ibanValid(iban), iban)
+		}
+	}
+	assert.Regexp(t, `^GB\d\d[A-Z]{4}\d{14}$`, New(map[string]interface{}{"type": "iban", "country": "GB"}).GetData())
+
+	generator := New(map[string]interface{}{"type": "iban"})
+	for i := 0; i < 50; i++ {
+		iban := generator.GetData().(string)
+		assert.Contains(t, ibanFormats, iban[:2], iban)
+		assert.True(t, ibanValid(iban), iban)
+	}
+	_, err := Build(map[string]interface{}{"type": "iban", "country": "US"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,296 @@
+package faker
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FakePHPSerialized applies generators to the values of the named keys in PHP serialize() data, at any depth
+// Array keys and object properties, private and protected ones included, are matched by name.
+// Strings are written back with their length in bytes. Values that are not valid serialized data become NULL and are counted
+type FakePHPSerialized struct {
+	Keys map[string]FakeGenerator
+
+	nested  []nestedGenerator
+	invalid int
+}
+
+// phpValue is a serialized value, arrays and objects hold the keys and values in turn
+type phpValue struct {
+	kind    byte
+	scalar  string
+	class   string
+	entries []*phpValue
+}
+
+var errPHPSerialized = errors.New("invalid serialized data")
+
+func newFakePHPSerialized(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	nested, err := nestedGenerators(fakeConfig, "keys", "key")
+	if err != nil {
+		return nil, err
+	}
+	fp := &FakePHPSerialized{Keys: make(map[string]FakeGenerator, len(nested)), nested: nested}
+	for _, n := range nested {
+		fp.Keys[n.name] = n.generator
+	}
+	return fp, nil
+}
+
+func (fp *FakePHPSerialized) SetColumn(column Column) {
+	setNestedColumn(fp.nested, column)
+}
+
+// GetData needs the original data
+func (fp *FakePHPSerialized) GetData() interface{} {
+	return nil
+}
+
+func (fp *FakePHPSerialized) Invalid() int {
+	return fp.invalid
+}
+
+func (fp *FakePHPSerialized) TransformRow(original interface{}, row *Row) interface{} {
+	if original == nil {
+		return nil
+	}
+	data := []byte(toString(original))
+	value, n, err := parsePHP(data)
+	if err != nil || n != len(data) {
+		fp.invalid++
+		return nil
+	}
+	fp.apply(value, row)
+
+	var b bytes.Buffer
+	value.encode(&b)
+	return b.String()
+}
+
+// apply replaces the scalar values of the named keys in the arrays and the objects
+func (fp *FakePHPSerialized) apply(value *phpValue, row *Row) {
+	for i := 0; i+1 < len(value.entries); i += 2 {
+		key, entry := value.entries[i], value.entries[i+1]
+		if generator, ok := fp.Keys[phpPropertyName(key.scalar)]; ok && entry.isScalar() {
+			*entry = *phpFake(FakeRow(generator, entry.original(), row))
+			continue
+		}
+		fp.apply(entry, row)
+	}
+}
+
+// phpPropertyName strips the class of private and the asterisk of protected properties, "\x00Class\x00name" is name
+func phpPropertyName(key string) string {
+	if strings.HasPrefix(key, "\x00") {
+		if i := strings.IndexByte(key[1:], 0); i >= 0 {
+			return key[i+2:]
+		}
+	}
+	return key
+}
+
+// parsePHP reads the value at the start of data and tells how many bytes it takes
+func parsePHP(data []byte) (*phpValue, int, error) {
+	if len(data) < 2 {
+		return nil, 0, errPHPSerialized
+	}
+	value := &phpValue{kind: data[0]}
+	switch value.kind {
+	case 'N':
+		if data[1] != ';' {
+			return nil, 0, errPHPSerialized
+		}
+		return value, 2, nil
+
+	case 'b', 'i', 'd', 'r', 'R':
+		end := bytes.IndexByte(data, ';')
+		if data[1] != ':' || end < 0 {
+			return nil, 0, errPHPSerialized
+		}
+		value.scalar = string(data[2:end])
+		return value, end + 1, nil
+
+	case 's', 'E':
+		s, n, err := parsePHPString(data[1:])
+		if err != nil || n+1 >= len(data) || data[n+1] != ';' {
+			return nil, 0, errPHPSerialized
+		}
+		value.scalar = s
+		return value, n + 2, nil
+
+	case 'a', 'O', 'C':
+		pos := 1
+		if value.kind != 'a' {
+			class, n, err := parsePHPString(data[pos:])
+			if err != nil {
+				return nil, 0, errPHPSerialized
+			}
+			value.class = class
+			pos += n
+		}
+		if value.kind == 'C' {
+			// Data of a class implementing Serializable is written by the class, it is kept as is
+			raw, n, err := parsePHPLength(data[pos:], '{', '}')
+			if err != nil {
+				return nil, 0, errPHPSerialized
+			}
+			value.scalar = raw
+			return value, pos + n, nil
+		}
+
+		count, n, err := parsePHPCount(data[pos:])
+		if err != nil {
+			return nil, 0, errPHPSerialized
+		}
+		pos += n
+		for i := 0; i < 2*count; i++ {
+			entry, n, err := parsePHP(data[pos:])
+			if err != nil {
+				return nil, 0, err
+			}
+			value.entries = append(value.entries, entry)
+			pos += n
+		}
+		if pos >= len(data) || data[pos] != '}' {
+			return nil, 0, errPHPSerialized
+		}
+		return value, pos + 1, nil
+	}
+	return nil, 0, errPHPSerialized
+}
+
+// parsePHPString reads :length:"bytes" and tells how many bytes it takes
+func parsePHPString(data []byte) (string, int, error) {
+	return parsePHPLength(data, '"', '"')
+}
+
+// parsePHPLength reads :length:<open>bytes<close> and tells how many bytes it takes
+func parsePHPLength(data []byte, open, close byte) (string, int, error) {
+	if len(data) == 0 || data[0] != ':' {
+		return "", 0, errPHPSerialized
+	}
+	end := bytes.IndexByte(data[1:], ':')
+	if end < 0 {
+		return "", 0, errPHPSerialized
+	}
+	length, err := strconv.Atoi(string(data[1 : end+1]))
+	start := end + 3
+	if err != nil || length < 0 || start+length >= len(data) || data[end+2] != open || data[start+length] != close {
+		return "", 0, errPHPSerialized
+	}
+	return string(data[start : start+length]), start + length + 1, nil
+}
+
+// parsePHPCount reads :count:{ of arrays and objects and tells how many bytes it takes
+func parsePHPCount(data []byte) (int, int, error) {
+	end := bytes.IndexByte(data, '{')
+	if len(data) == 0 || data[0] != ':' || end < 2 || data[end-1] != ':' {
+		return 0, 0, errPHPSerialized
+	}
+	count, err := strconv.Atoi(string(data[1 : end-1]))
+	if err != nil || count < 0 {
+		return 0, 0, errPHPSerialized
+	}
+	return count, end + 1, nil
+}
+
+func (value *phpValue) isScalar() bool {
+	switch value.kind {
+	case 'N', 'b', 'i', 'd', 's':
+		return true
+	}
+	return false
+}
+
+// original gives the value to the generators as Go value
+func (value *phpValue) original() interface{} {
+	switch value.kind {
+	case 'b':
+		return value.scalar == "1"
+	case 'i':
+		if i, err := strconv.ParseInt(value.scalar, 10, 64); err == nil {
+			return i
+		}
+	case 'd':
+		if f, err := strconv.ParseFloat(value.scalar, 64); err == nil {
+			return f
+		}
+	case 's':
+		return value.scalar
+	}
+	return nil
+}
+
+// phpFake converts the fake value to a serialized value
+func phpFake(fake interface{}) *phpValue {
+	switch v := fake.(type) {
+	case nil:
+		return &phpValue{kind: 'N'}
+	case bool:
+		if v {
+			return &phpValue{kind: 'b', scalar: "1"}
+		}
+		return &phpValue{kind: 'b', scalar: "0"}
+	case int64:
+		return &phpValue{kind: 'i', scalar: strconv.FormatInt(v, 10)}
+	case float64:
+		return &phpValue{kind: 'd', scalar: phpFloat(v)}
+	case Decimal:
+		if _, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return &phpValue{kind: 'i', scalar: string(v)}
+		}
+		return &phpValue{kind: 'd', scalar: string(v)}
+	}
+	return &phpValue{kind: 's', scalar: toString(fake)}
+}
+
+// phpFloat writes the float the way serialize() does
+func phpFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NAN"
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// encode writes the value back, strings get their length in bytes
+func (value *phpValue) encode(b *bytes.Buffer) {
+	switch value.kind {
+	case 'N':
+		b.WriteString("N;")
+	case 'b', 'i', 'd', 'r', 'R':
+		b.WriteByte(value.kind)
+		b.WriteByte(':')
+		b.WriteString(value.scalar)
+		b.WriteByte(';')
+	case 's', 'E':
+		b.WriteByte(value.kind)
+		writePHPString(b, value.scalar)
+		b.WriteByte(';')
+	case 'C':
+		b.WriteByte('C')
+		writePHPString(b, value.class)
+		b.WriteString(":" + strconv.Itoa(len(value.scalar)) + ":{" + value.scalar + "}")
+	case 'a', 'O':
+		b.WriteByte(value.kind)
+		if value.kind == 'O' {
+			writePHPString(b, value.class)
+		}
+		b.WriteString(":" + strconv.Itoa(len(value.entries)/2) + ":{")
+		for _, entry := range value.entries {
+			entry.encode(b)
+		}
+		b.WriteByte('}')
+	}
+}
+
+func writePHPString(b *bytes.Buffer, s string) {
+	b.WriteString(":" + strconv.Itoa(len(s)) + ":\"" + s + "\"")
+}
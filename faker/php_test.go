@@ -0,0 +1,55 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakePHPSerialized(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "php_serialized", "keys": []interface{}{
+		map[interface{}]interface{}{"key": "email", "type": "fixed", "string": "zoë@example.test"},
+		map[string]interface{}{"key": "name", "type": "fixed", "string": "Zoë"},
+		map[string]interface{}{"key": "age", "type": "round", "step": 10},
+		map[string]interface{}{"key": "id", "type": "fixed", "string": "ignored"},
+	}})
+	assert.NoError(t, err)
+
+	original := `a:5:{s:5:"email";s:12:"john@doe.com";s:4:"name";N;s:3:"age";i:42;` +
+		`s:4:"user";O:4:"User":2:{s:11:"` + "\x00User\x00" + `email";s:5:"a@b.c";s:5:"` + "\x00*\x00" + `id";a:1:{i:0;i:7;}}` +
+		`i:0;C:3:"Foo":5:{email}}`
+	expected := `a:5:{s:5:"email";s:17:"zoë@example.test";s:4:"name";s:4:"Zoë";s:3:"age";i:40;` +
+		`s:4:"user";O:4:"User":2:{s:11:"` + "\x00User\x00" + `email";s:17:"zoë@example.test";s:5:"` + "\x00*\x00" + `id";a:1:{i:0;i:7;}}` +
+		`i:0;C:3:"Foo":5:{email}}`
+	assert.Equal(t, expected, Fake(generator, []byte(original)))
+}
+
+func TestFakePHPSerializedScalars(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "php_serialized", "keys": []interface{}{
+		map[string]interface{}{"key": "score", "type": "round", "step": 0.5},
+		map[string]interface{}{"key": "enabled", "type": "fixed"},
+	}})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `a:3:{s:5:"score";d:0.5;s:7:"enabled";s:0:"";s:4:"suit";E:11:"Suit:Hearts";}`,
+		Fake(generator, `a:3:{s:5:"score";d:0.7;s:7:"enabled";b:1;s:4:"suit";E:11:"Suit:Hearts";}`))
+	assert.Equal(t, `s:5:"score";`, Fake(generator, `s:5:"score";`))
+}
+
+func TestFakePHPSerializedInvalid(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "php_serialized", "keys": []interface{}{
+		map[string]interface{}{"key": "email", "type": "email"},
+	}})
+	assert.NoError(t, err)
+	for _, original := range []interface{}{nil, "", "not serialized", `s:5:"abc";`, `a:2:{s:1:"a";i:1;}`, `i:1;i:2;`, `a:1:{s:1:"a";i:1;`} {
+		assert.Nil(t, Fake(generator, original), original)
+	}
+	// NULL is not counted
+	assert.Equal(t, 6, generator.(InvalidCounter).Invalid())
+
+	_, err = Build(map[string]interface{}{"type": "php_serialized"})
+	assert.EqualError(t, err, "keys are missing")
+	_, err = Build(map[string]interface{}{"type": "php_serialized", "keys": []interface{}{map[string]interface{}{"type": "email"}}})
+	assert.EqualError(t, err, "key 1 must have a key and a type")
+}
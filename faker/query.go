@@ -0,0 +1,74 @@
+package faker
+
+import (
+	"net/url"
+	"strings"
+)
+
+// FakeQueryString applies generators to the values of the named keys in URL-encoded data, e.g. a=1&user[email]=a%40b.c
+// Keys are matched once decoded, the other pairs are kept as they are written
+type FakeQueryString struct {
+	Keys map[string]FakeGenerator
+
+	nested []nestedGenerator
+}
+
+func newFakeQueryString(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	nested, err := nestedGenerators(fakeConfig, "keys", "key")
+	if err != nil {
+		return nil, err
+	}
+	fq := &FakeQueryString{Keys: make(map[string]FakeGenerator, len(nested)), nested: nested}
+	for _, n := range nested {
+		fq.Keys[n.name] = n.generator
+	}
+	return fq, nil
+}
+
+func (fq *FakeQueryString) SetColumn(column Column) {
+	setNestedColumn(fq.nested, column)
+}
+
+// GetData needs the original data
+func (fq *FakeQueryString) GetData() interface{} {
+	return nil
+}
+
+func (fq *FakeQueryString) TransformRow(original interface{}, row *Row) interface{} {
+	if original == nil {
+		return nil
+	}
+	query := toString(original)
+	prefix := ""
+	if strings.HasPrefix(query, "?") {
+		prefix, query = "?", query[1:]
+	}
+
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		rawKey, rawValue := pair, ""
+		j := strings.IndexByte(pair, '=')
+		if j >= 0 {
+			rawKey, rawValue = pair[:j], pair[j+1:]
+		}
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+		generator, ok := fq.Keys[key]
+		if !ok || j < 0 {
+			continue
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			value = rawValue
+		}
+		fake := FakeRow(generator, value, row)
+		if fake == nil {
+			pairs[i] = rawKey + "="
+			continue
+		}
+		pairs[i] = rawKey + "=" + url.QueryEscape(toString(fake))
+	}
+	return prefix + strings.Join(pairs, "&")
+}
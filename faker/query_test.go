@@ -0,0 +1,22 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeQueryString(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "query_string", "keys": []interface{}{
+		map[string]interface{}{"key": "email", "type": "fixed", "string": "x+y@example.test"},
+		map[string]interface{}{"key": "user[phone]", "type": "mask", "keepEnd": 2},
+		map[string]interface{}{"key": "token", "type": "hash", "salt": "s", "length": 8},
+	}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "?email=x%2By%40example.test&lang=fr%20FR&user%5Bphone%5D=%2A%2A%2A%2A%2A%2A%2A%2A45&flag&empty=",
+		Fake(generator, "?email=john%40doe.com&lang=fr%20FR&user%5Bphone%5D=0102030445&flag&empty="))
+	assert.Equal(t, "token=cf0bbce2", Fake(generator, []byte("token=abc")))
+	assert.Equal(t, "", Fake(generator, ""))
+	assert.Nil(t, Fake(generator, nil))
+}
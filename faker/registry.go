@@ -0,0 +1,58 @@
+package faker
+
+import (
+	"fmt"
+	"sort"
+
+	oneFake "github.com/manveru/faker"
+)
+
+// Factory creates a generator from the options of the column config, the error tells what is wrong with them
+// The options hold the type and any other keys of the column, e.g. {type: sku, prefix: AB}
+type Factory func(fakeConfig map[string]interface{}) (FakeGenerator, error)
+
+var factories = make(map[string]Factory)
+
+// Register makes the factory available as the type of obfuscated columns
+// It is meant to be called from init functions, like database/sql.Register it panics
+// if the name is empty, the factory is nil or the name is already registered
+func Register(name string, factory Factory) {
+	if name == "" {
+		panic("faker: Register type name is empty")
+	}
+	if factory == nil {
+		panic("faker: Register factory is nil for type " + name)
+	}
+	if _, dup := factories[name]; dup {
+		panic(fmt.Sprintf("faker: Register called twice for type %s", name))
+	}
+	factories[name] = factory
+}
+
+// Types lists the registered types
+func Types() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// simpleFactory is the factory of a generator without options
+func simpleFactory(create func() FakeGenerator) Factory {
+	return func(map[string]interface{}) (FakeGenerator, error) {
+		return create(), nil
+	}
+}
+
+// localeFactory is the factory of a generator with the locale option only
+func localeFactory(create func(lf *oneFake.Faker) FakeGenerator) Factory {
+	return func(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+		lf, err := localeFaker(fakeConfig)
+		if err != nil {
+			return nil, err
+		}
+		return create(lf), nil
+	}
+}
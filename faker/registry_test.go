@@ -0,0 +1,50 @@
+package faker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSKU struct {
+	prefix string
+}
+
+func (fs *fakeSKU) GetData() interface{} {
+	return fs.prefix + "-0001"
+}
+
+func init() {
+	Register("test_sku", func(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+		prefix, ok := stringOption(fakeConfig, "prefix")
+		if !ok || prefix == "" {
+			return nil, errors.New("prefix is missing")
+		}
+		return &fakeSKU{prefix: prefix}, nil
+	})
+}
+
+func TestRegisteredType(t *testing.T) {
+	generator, err := Build(map[string]interface{}{"type": "test_sku", "prefix": "AB"})
+	assert.NoError(t, err)
+	assert.Equal(t, "AB-0001", generator.GetData())
+
+	_, err = Build(map[string]interface{}{"type": "test_sku"})
+	assert.EqualError(t, err, "prefix is missing")
+
+	template, err := Build(map[string]interface{}{"type": "template", "template": "{{test_sku}}", "prefix": "CD"})
+	assert.EqualError(t, err, "placeholder {{test_sku}}: prefix is missing")
+	assert.Nil(t, template)
+
+	assert.Contains(t, Types(), "test_sku")
+	assert.Contains(t, Types(), TypeEmail)
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	factory := simpleFactory(func() FakeGenerator { return &FakeEmail{} })
+	assert.PanicsWithValue(t, "faker: Register called twice for type email", func() { Register(TypeEmail, factory) })
+	assert.Panics(t, func() { Register("", factory) })
+	assert.Panics(t, func() { Register("test_nil", nil) })
+	assert.NotContains(t, Types(), "test_nil")
+}
@@ -0,0 +1,110 @@
+package faker
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var placeholderRe = regexp.MustCompile(`\{\{\s*([a-z0-9_]+)\s*(?:\|\s*([a-z]+)\s*)?\}\}`)
+
+// FakeTemplate fills {{type}} placeholders of the template with values of other generators
+// A placeholder could be filtered with lower or upper, e.g. {{first_name | lower}}
+type FakeTemplate struct {
+	Template string
+	parts    []templatePart
+}
+
+// templatePart is either a literal text or a generated value
+type templatePart struct {
+	text      string
+	generator FakeGenerator
+	filter    func(string) string
+}
+
+var templateFilters = map[string]func(string) string{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+func newFakeTemplate(fakeConfig map[string]interface{}) (FakeGenerator, error) {
+	template, ok := stringOption(fakeConfig, "template")
+	if !ok || template == "" {
+		return nil, errors.New("template is missing")
+	}
+
+	ft := &FakeTemplate{Template: template}
+	last := 0
+	for _, m := range placeholderRe.FindAllStringSubmatchIndex(template, -1) {
+		if m[0] > last {
+			ft.parts = append(ft.parts, templatePart{text: template[last:m[0]]})
+		}
+		last = m[1]
+
+		name := template[m[2]:m[3]]
+		placeholderConfig := map[string]interface{}{"type": name}
+		if locale := option(fakeConfig, "locale"); locale != nil {
+			placeholderConfig["locale"] = locale
+		}
+		generator, err := Build(placeholderConfig)
+		if err != nil {
+			return nil, fmt.Errorf("placeholder {{%s}}: %v", name, err)
+		}
+		if err := checkPlaceholder(generator); err != nil {
+			return nil, fmt.Errorf("placeholder {{%s}}: %v", name, err)
+		}
+		part := templatePart{generator: generator}
+		if m[4] >= 0 {
+			filterName := template[m[4]:m[5]]
+			if part.filter, ok = templateFilters[filterName]; !ok {
+				return nil, fmt.Errorf("placeholder {{%s}}: unknown filter %s", name, filterName)
+			}
+		}
+		ft.parts = append(ft.parts, part)
+	}
+	if last < len(template) {
+		ft.parts = append(ft.parts, templatePart{text: template[last:]})
+	}
+	for _, part := range ft.parts {
+		if part.generator == nil && strings.Contains(part.text, "{{") {
+			return nil, errors.New("template has a malformed placeholder")
+		}
+	}
+	return ft, nil
+}
+
+// checkPlaceholder tells why the generator can't fill a placeholder, the placeholder has no options, original value or column
+func checkPlaceholder(generator FakeGenerator) error {
+	switch generator.(type) {
+	case Transformer, RowTransformer:
+		return errors.New("type needs the original value")
+	case *FakeNull:
+		return errors.New("NULL has no text, leave the placeholder out")
+	case *FakeDefault:
+		return errors.New("type needs the column, the template has none")
+	case *FakeFixed:
+		return errors.New("type has no value, write the text into the template")
+	}
+	return nil
+}
+
+func (ft *FakeTemplate) GetData() interface{} {
+	var b strings.Builder
+	for _, part := range ft.parts {
+		if part.generator == nil {
+			b.WriteString(part.text)
+			continue
+		}
+		value := part.generator.GetData()
+		if value == nil {
+			continue
+		}
+		s := toString(value)
+		if part.filter != nil {
+			s = part.filter(s)
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package faker
+
+// Transformer is implemented by generators that derive the fake value from the original one
+type Transformer interface {
+	FakeGenerator
+	Transform(original interface{}) interface{}
+}
+
+// RowTransformer is implemented by generators that depend on other columns of the row
+type RowTransformer interface {
+	FakeGenerator
+	TransformRow(original interface{}, row *Row) interface{}
+}
+
+// Row is the row being dumped
+type Row struct {
+	Table string
+	// Number counts rows of the table from 1
+	Number int64
+	// Values are the original values by column name
+	Values map[string]interface{}
+}
+
+// Column describes the column a generator fills
+type Column struct {
+	Name string
+	// Type is the database column type, e.g. varchar(255) or timestamp without time zone
+	Type      string
+	MaxLength int
+	Nullable  bool
+	// Default is the default value, nil if there is none or it is NULL
+	Default *string
+	// DefaultExpr is the default expression that is not a value, e.g. CURRENT_TIMESTAMP
+	DefaultExpr string
+}
+
+// ColumnAware is implemented by generators that format values for the column type
+type ColumnAware interface {
+	SetColumn(column Column)
+}
+
+// ColumnChecker is implemented by generators that can't fill any column
+type ColumnChecker interface {
+	// CheckColumn tells why the generator can't fill the column
+	CheckColumn(column Column) error
+}
+
+// Wrapper is implemented by generators that change values of another generator
+type Wrapper interface {
+	Unwrap() FakeGenerator
+}
+
+// Sampler is implemented by generators that follow the distribution of the original values
+type Sampler interface {
+	// NeedsSample tells whether the original values should be counted before the dump
+	NeedsSample() bool
+	// SetSample gives the distinct original values with the number of rows having each of them
+	SetSample(values []interface{}, counts []int64)
+}
+
+// Batcher is implemented by generators that transform the original values of several rows at once
+type Batcher interface {
+	// BatchSize is the number of rows read ahead
+	BatchSize() int
+	// Prepare gives the original values of the upcoming rows before they are transformed one by one
+	Prepare(table string, originals []interface{}) error
+}
+
+// Warner is implemented by generators whose options are valid but likely not what was meant
+type Warner interface {
+	// Warning describes the problem, it is empty if there is none
+	Warning() string
+}
+
+// InvalidCounter is implemented by generators that write NULL for the original values they can't parse
+type InvalidCounter interface {
+	// Invalid is the number of such values so far
+	Invalid() int
+}
+
+// Fake returns the fake value replacing the original one
+func Fake(generator FakeGenerator, original interface{}) interface{} {
+	return FakeRow(generator, original, nil)
+}
+
+// FakeRow returns the fake value replacing the original one of the row, row could be nil
+func FakeRow(generator FakeGenerator, original interface{}, row *Row) interface{} {
+	switch g := generator.(type) {
+	case RowTransformer:
+		return g.TransformRow(original, row)
+	case Transformer:
+		return g.Transform(original)
+	}
+	return generator.GetData()
+}
+
+// SetColumn tells the generator and the wrapped ones which column they fill if they care
+func SetColumn(generator FakeGenerator, column Column) {
+	for ; generator != nil; generator = Unwrap(generator) {
+		if aware, ok := generator.(ColumnAware); ok {
+			aware.SetColumn(column)
+		}
+	}
+}
+
+// CheckColumn tells why the generator or a wrapped one can't fill the column
+func CheckColumn(generator FakeGenerator, column Column) error {
+	for ; generator != nil; generator = Unwrap(generator) {
+		if checker, ok := generator.(ColumnChecker); ok {
+			if err := checker.CheckColumn(column); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Warnings lists the warnings of the generator and of the wrapped ones
+func Warnings(generator FakeGenerator) []string {
+	var warnings []string
+	for ; generator != nil; generator = Unwrap(generator) {
+		if warner, ok := generator.(Warner); ok && warner.Warning() != "" {
+			warnings = append(warnings, warner.Warning())
+		}
+	}
+	return warnings
+}
+
+// Unwrap returns the generator wrapped by this one, nil if it is not a wrapper
+func Unwrap(generator FakeGenerator) FakeGenerator {
+	if wrapper, ok := generator.(Wrapper); ok {
+		return wrapper.Unwrap()
+	}
+	return nil
+}
+
+// GetSampler returns the generator or the wrapped one that needs a sample of the original values, nil if none does
+func GetSampler(generator FakeGenerator) Sampler {
+	for ; generator != nil; generator = Unwrap(generator) {
+		if sampler, ok := generator.(Sampler); ok && sampler.NeedsSample() {
+			return sampler
+		}
+	}
+	return nil
+}
+
+// GetBatcher returns the generator or the wrapped one that transforms values in batches, nil if none does
+func GetBatcher(generator FakeGenerator) Batcher {
+	for ; generator != nil; generator = Unwrap(generator) {
+		if batcher, ok := generator.(Batcher); ok {
+			return batcher
+		}
+	}
+	return nil
+}
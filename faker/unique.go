@@ -0,0 +1,59 @@
+package faker
+
+import "fmt"
+
+// Duplicated values are generated again this many times before the generator gives up
+const maxUniqueAttempts = 100
+
+// Failing is implemented by generators that can run out of values
+type Failing interface {
+	// Err returns the reason why the last value could not be generated
+	Err() error
+}
+
+// UniqueGenerator never returns the same value twice, NULL is not tracked
+type UniqueGenerator struct {
+	Generator FakeGenerator
+
+	issued map[string]struct{}
+	err    error
+}
+
+// NewUniqueGenerator wraps the generator, nil is returned as is
+func NewUniqueGenerator(generator FakeGenerator) FakeGenerator {
+	if generator == nil {
+		return nil
+	}
+	return &UniqueGenerator{
+		Generator: generator,
+		issued:    make(map[string]struct{}),
+	}
+}
+
+func (ug *UniqueGenerator) Unwrap() FakeGenerator {
+	return ug.Generator
+}
+
+func (ug *UniqueGenerator) GetData() interface{} {
+	return ug.TransformRow(nil, nil)
+}
+
+func (ug *UniqueGenerator) TransformRow(original interface{}, row *Row) interface{} {
+	for i := 0; i < maxUniqueAttempts; i++ {
+		data := FakeRow(ug.Generator, original, row)
+		if data == nil {
+			return nil
+		}
+		key := fmt.Sprintf("%v", data)
+		if _, ok := ug.issued[key]; !ok {
+			ug.issued[key] = struct{}{}
+			return data
+		}
+	}
+	ug.err = fmt.Errorf("no unique value is found in %d attempts after %d values, the generator is exhausted", maxUniqueAttempts, len(ug.issued))
+	return nil
+}
+
+func (ug *UniqueGenerator) Err() error {
+	return ug.err
+}
@@ -0,0 +1,23 @@
+package faker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniqueGeneratorSkipsDuplicates(t *testing.T) {
+	generator := NewUniqueGenerator(&sequence{values: []interface{}{"a", "a", "b", nil, "a", "c"}})
+	assert.Equal(t, "a", generator.GetData())
+	assert.Equal(t, "b", generator.GetData())
+	assert.Nil(t, generator.GetData())
+	assert.Equal(t, "c", generator.GetData())
+	assert.NoError(t, generator.(Failing).Err())
+}
+
+func TestUniqueGeneratorExhausted(t *testing.T) {
+	generator := NewUniqueGenerator(&FakeFixed{Value: "same"})
+	assert.Equal(t, "same", generator.GetData())
+	assert.Nil(t, generator.GetData())
+	assert.EqualError(t, generator.(Failing).Err(), "no unique value is found in 100 attempts after 1 values, the generator is exhausted")
+}
@@ -8,7 +8,9 @@ import (
 	"html/template"
 	"os"
 	"path/filepath"
+	"strings"
 
+	_ "github.com/lib/pq"
 	"github.com/vicdeo/go-obfuscate/config"
 	"github.com/vicdeo/go-obfuscate/mysqldump"
 )
@@ -24,6 +26,13 @@ const (
 	errConfigHasDuplicates     = 7
 	errConfigIncomplete        = 8
 	errConfigHasUnknownType    = 9
+	errUnknownCommand          = 10
+	errDumpFailed              = 11
+	errDumpVerificationFailed  = 12
+	errRestoreFailed           = 13
+	errConfigFileExists        = 14
+	errConfigHasInvalidFaker   = 15
+	errConfigHasInvalidExclude = 16
 
 	statsTemplate = `Config parsed. Found tables count:
  - to dump as is: {{.keep}}
@@ -48,8 +57,17 @@ Total: {{.total}}
 {{end}}{{end}}
 `
 
-	fakerValidationTemplate = `Checking obfuscated columns type...done
-{{range $v := .}} - Column {{index $v 1}} in the table {{index $v 0}} has unknown or missing type
+	fakerValidationTemplate = `Checking obfuscated columns options...done
+{{range $v := .}}{{if index $v 0}} - Column {{index $v 1}} in the table {{index $v 0}}: {{index $v 2}}
+{{else}} - Option {{index $v 1}}: {{index $v 2}}
+{{end}}{{end}}
+`
+	columnValidationTemplate = `Checking obfuscated columns in DB...done
+{{range $v := .}} - Column {{index $v 1}} in the table {{index $v 0}}: {{index $v 2}}
+{{end}}
+`
+	excludeValidationTemplate = `Checking exclude rules...done
+{{range $v := .}} - Table {{index $v 0}}: {{index $v 1}}
 {{end}}
 `
 )
@@ -58,59 +76,65 @@ var (
 	conf *config.Config
 )
 
-func init() {
-	loadConfig()
-	prepareFS()
+// command is a go-obfuscate subcommand
+type command struct {
+	name        string
+	description string
+	run         func(args []string)
+}
+
+var commands = []command{
+	{"validate", "check the config file without connecting to the database", runValidate},
+	{"check", "check the config file against the database", runCheck},
+	{"dump", "create an obfuscated dump (default)", runDump},
+	{"init", "write a config file skeleton", runInit},
+	{"verify", "check that a dump is complete", runVerify},
+	{"restore", "load a SQL dump into another database", runRestore},
+	{"version", "print the version", runVersion},
 }
 
 func main() {
-	// Open connection to database
-	db, err := sql.Open("mysql", conf.Database.GetMysqlConfigDSN())
-	if err != nil {
-		fmt.Println("Error opening database: ", err)
+	args := os.Args[1:]
+	// go-obfuscate [-c config.yaml] is a dump as before subcommands were introduced
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		runDump(args)
 		return
 	}
 
-	err = db.Ping()
-	exitOnError(err != nil, errDBConnectionFailed, fmt.Sprintf("Please validate DB credentials.\n%v", err))
-
-	allConfigTables := conf.GetAllUniqueTableNames()
-	allDbTables, err := mysqldump.ShowTables(db)
-	exitOnError(err != nil, errShowTablesFailed, fmt.Sprintf("Error getting database table list: %v", err))
-
-	diff, diff2 := difference(allConfigTables, allDbTables)
-	dbValTmpl, err := template.New("dbValidation").Parse(dbValidationTemplate)
-	if err == nil {
-		dbValTmpl.Execute(os.Stdout, map[string][]string{
-			"missedInDb":     diff,
-			"missedInConfig": diff2,
-		})
+	name := args[0]
+	if name == "help" {
+		usage()
+		return
 	}
-	exitOnError(len(diff) > 0 || len(diff2) > 0, errConfigIncomplete, "Please fix the reported errors in your config file before proceeding")
-
-	// Register database with mysqldump
-	dumper, err := mysqldump.Register(db, conf)
-	exitOnError(err != nil, errDumpFileIsNotWritable, fmt.Sprintf("Error registering database: %v", err))
+	for _, cmd := range commands {
+		if cmd.name == name {
+			cmd.run(args[1:])
+			return
+		}
+	}
+	usage()
+	exitOnError(true, errUnknownCommand, fmt.Sprintf("Unknown command: %s", name))
+}
 
-	// TODO: add to config support of	dumper.LockTables = true
-	err = dumper.Dump()
-	if err != nil {
-		fmt.Println("Error dumping:", err)
-		return
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [-c config.yaml] [options]\n\nCommands:\n", filepath.Base(os.Args[0]))
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.description)
 	}
-	fmt.Printf("File is saved to %s\n", conf.GetDumpFileName())
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the command options.\n", filepath.Base(os.Args[0]))
+}
 
-	// Close dumper, connected database and file stream.
-	dumper.Close()
+// newFlagSet creates a command flag set with the options shared by all commands
+func newFlagSet(name string, configFilePath *string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.StringVar(configFilePath, "c", "./config.yaml", "Config file path")
+	return flags
 }
 
-func loadConfig() {
-	var configFilePath string
+func loadConfig(configFilePath string) {
 	var error error
 
 	fmt.Println("go-obfuscate version", version)
-	flag.StringVar(&configFilePath, "c", "./config.yaml", "MySQL connection details(./config.yaml)")
-	flag.Parse()
 	evaledPath, _ := filepath.EvalSymlinks(configFilePath)
 	_, err := os.Stat(evaledPath)
 	exitOnError(errors.Is(err, os.ErrNotExist), errConfigFileNotFound, fmt.Sprintf("Config file does not exist: %s", configFilePath))
@@ -118,7 +142,12 @@ func loadConfig() {
 	fmt.Println("Using config file:", evaledPath)
 	conf, error = config.GetConf(filepath.Dir(evaledPath), filepath.Base(evaledPath))
 	exitOnError(error != nil, errConfigFileInvalidMarkUp, fmt.Sprintf("Config file contains invalid YAML markup:\n%v\n", error))
+	exitOnError(!conf.Database.HasKnownDriver(), errConfigHasUnknownType, fmt.Sprintf("Unknown database driver: %s", conf.Database.Driver))
+	exitOnError(!conf.Output.HasKnownFormat(), errConfigHasUnknownType, fmt.Sprintf("Unknown output format: %s", conf.Output.Format))
+}
 
+// validateConfig runs the sanity checks that don't need a database connection
+func validateConfig() {
 	statsTmpl, err := template.New("statistics").Parse(statsTemplate)
 	if err == nil {
 		statsTmpl.Execute(os.Stdout, map[string]int{
@@ -144,7 +173,55 @@ func loadConfig() {
 	if err == nil {
 		fakerTmpl.Execute(os.Stdout, unknown)
 	}
-	exitOnError(hasErrors, errConfigHasDuplicates, "Please fix the reported errors in your config file before proceeding")
+	exitOnError(hasErrors, errConfigHasInvalidFaker, "Please fix the reported errors in your config file before proceeding")
+
+	// Sanity check 3: each exclude rule should be valid
+	invalid, hasErrors := conf.ValidateExcludeSection()
+	excludeTmpl, err := template.New("exclude").Parse(excludeValidationTemplate)
+	if err == nil {
+		excludeTmpl.Execute(os.Stdout, invalid)
+	}
+	exitOnError(hasErrors, errConfigHasInvalidExclude, "Please fix the reported errors in your config file before proceeding")
+}
+
+// connectDB opens the source database connection
+func connectDB() *sql.DB {
+	return openDB(conf.Database.GetDSN())
+}
+
+func openDB(dsn string) *sql.DB {
+	db, err := sql.Open(conf.Database.GetDriver(), dsn)
+	exitOnError(err != nil, errDBConnectionFailed, fmt.Sprintf("Error opening database: %v", err))
+
+	err = db.Ping()
+	exitOnError(err != nil, errDBConnectionFailed, fmt.Sprintf("Please validate DB credentials.\n%v", err))
+	return db
+}
+
+// checkDB runs the sanity checks against the database
+func checkDB(db *sql.DB) {
+	allConfigTables := conf.GetAllUniqueTableNames()
+	dialect, _ := mysqldump.NewDialect(conf.Database.GetDriver())
+	allDbTables, err := mysqldump.ShowTables(db, dialect)
+	exitOnError(err != nil, errShowTablesFailed, fmt.Sprintf("Error getting database table list: %v", err))
+
+	diff, diff2 := difference(allConfigTables, allDbTables)
+	dbValTmpl, err := template.New("dbValidation").Parse(dbValidationTemplate)
+	if err == nil {
+		dbValTmpl.Execute(os.Stdout, map[string][]string{
+			"missedInDb":     diff,
+			"missedInConfig": diff2,
+		})
+	}
+	exitOnError(len(diff) > 0 || len(diff2) > 0, errConfigIncomplete, "Please fix the reported errors in your config file before proceeding")
+
+	invalid, err := mysqldump.CheckColumns(db, dialect, conf.ObfuscatedColumns())
+	exitOnError(err != nil, errShowTablesFailed, fmt.Sprintf("Error getting database column list: %v", err))
+	columnTmpl, err := template.New("columnValidation").Parse(columnValidationTemplate)
+	if err == nil {
+		columnTmpl.Execute(os.Stdout, invalid)
+	}
+	exitOnError(len(invalid) > 0, errConfigIncomplete, "Please fix the reported errors in your config file before proceeding")
 }
 
 func prepareFS() {
@@ -0,0 +1,78 @@
+package mysqldump
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/vicdeo/go-obfuscate/config"
+)
+
+// Dialect hides database specific queries, quoting and dump templates
+type Dialect interface {
+	// QuoteIdentifier quotes a table or a column name
+	QuoteIdentifier(name string) string
+	// QuoteString returns a string literal
+	QuoteString(value string) string
+	// QuoteBinary returns a binary string literal
+	QuoteBinary(value []byte) string
+
+	// Templates returns header, table and footer templates of the SQL dump
+	Templates() (header, table, footer string)
+
+	// TablesQuery lists tables of the current database
+	TablesQuery() string
+	// ServerVersionQuery returns the server version
+	ServerVersionQuery() string
+	// MaxAllowedPacketQuery returns the largest allowed query size, empty if there is no such limit
+	MaxAllowedPacketQuery() string
+	// LockTablesQuery locks the tables for reading, empty if locking is not supported
+	LockTablesQuery(tables []string) string
+
+	// TableDefinition returns statements to recreate the table
+	TableDefinition(tx *sql.Tx, name string) (*tableDefinition, error)
+	// Columns returns columns of the table in their natural order
+	Columns(tx *sql.Tx, name string) ([]column, error)
+	// UniqueColumns returns columns that have a unique index of their own, primary key included
+	UniqueColumns(tx *sql.Tx, name string) (map[string]bool, error)
+	// MaxLengthQuery lists column names with their maximum length in characters, takes the table name
+	MaxLengthQuery() string
+}
+
+// tableDefinition holds statements that recreate a table
+type tableDefinition struct {
+	// Create goes before the table data
+	Create string
+	// PostData goes after the table data
+	PostData string
+	// Deferred go after all the tables are restored, e.g. foreign keys
+	Deferred []string
+}
+
+// column describes a table column
+type column struct {
+	Name     string
+	Type     string
+	Nullable bool
+	// Key is PRI for primary key columns, UNI for single column unique keys
+	Key     string
+	Default sql.NullString
+	// DefaultExpr tells whether Default is an SQL expression rather than a value
+	DefaultExpr bool
+	Extra       string
+	Generated   bool
+	// MaxLength is the maximum length in characters, 0 if not limited
+	MaxLength int
+}
+
+var dialects = map[string]Dialect{
+	config.DriverMySQL:    &mysqlDialect{},
+	config.DriverPostgres: &postgresDialect{},
+}
+
+// NewDialect returns the dialect for a database/sql driver name
+func NewDialect(driver string) (Dialect, error) {
+	if dialect, ok := dialects[driver]; ok {
+		return dialect, nil
+	}
+	return nil, fmt.Errorf("unsupported database driver %q", driver)
+}
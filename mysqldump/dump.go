@@ -7,7 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -19,23 +21,39 @@ import (
 /*
 Data struct to configure dump behavior
 
-    Out:              Stream to wite to
-    Connection:       Database connection to dump
-    IgnoreTables:     Mark sensitive tables to ignore
-    MaxAllowedPacket: Sets the largest packet size to use in backups
-    LockTables:       Lock all tables for the duration of the dump
+	Out:              Stream to wite to
+	Format:           Output format, SQL if not set
+	Directory:        Directory to write per-table files into for CSV and NDJSON formats
+	File:             Database file to write into for SQLite format
+	Connection:       Database connection to dump
+	Dialect:          Database specific SQL, MySQL if not set
+	IgnoreTables:     Mark sensitive tables to ignore
+	MaxAllowedPacket: Sets the largest packet size to use in backups, the server value capped at 1MB is used if not set
+	NullString:       Marker of NULL in CSV files
+	LockTables:       Lock all tables for the duration of the dump
+	Warnings:         Problems found during the dump that did not stop it
+	Excluded:         Number of rows dropped by the exclude rule per table
 */
 type Data struct {
 	Out              io.Writer
+	Format           string
+	Directory        string
+	File             string
 	Connection       *sql.DB
+	Dialect          Dialect
 	IgnoreTables     []string
 	MaxAllowedPacket int
+	NullString       string
 	LockTables       bool
+	Warnings         []string
+	Excluded         map[string]int64
 
 	tx         *sql.Tx
+	sqlite     *sql.DB
 	headerTmpl *template.Template
 	tableTmpl  *template.Template
 	footerTmpl *template.Template
+	deferred   []string
 	err        error
 }
 
@@ -44,16 +62,30 @@ type table struct {
 	Err  error
 
 	cols      []string
+	columns   []column
+	generated []string
+	colTypes  []*sql.ColumnType
 	colFakers []faker.FakeGenerator
 	data      *Data
 	rows      *sql.Rows
 	values    []interface{}
+	row       []interface{}
+	rowNumber int64
+	// pending are the original rows read ahead for the fakers transforming values in batches
+	pending   [][]interface{}
+	batchSize int
+	// exclude drops rows, the SQL predicate is read into excludeValue after the values of the row
+	exclude      *config.ExcludeRule
+	excludeValue *interface{}
+	excluded     int64
+	def          *tableDefinition
 }
 
 type metaData struct {
 	DumpVersion   string
 	ServerVersion string
 	CompleteTime  string
+	DeferredSQL   []string
 }
 
 const (
@@ -61,64 +93,13 @@ const (
 	Version = "0.7.0"
 
 	defaultMaxAllowedPacket = 4194304
-)
+	// Batch size used when the packet size is not set, the net_buffer_length default of mysqldump.
+	// The server limit of the source is often far above the limit of the server the dump is restored to
+	defaultBatchSize = 1046528
 
-// takes a *metaData
-const headerTmpl = `-- Go SQL Dump {{ .DumpVersion }}
---
--- ------------------------------------------------------
--- Server version	{{ .ServerVersion }}
-
-/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
-/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
-/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
- SET NAMES utf8mb4 ;
-/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
-/*!40103 SET TIME_ZONE='+00:00' */;
-/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
-/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
-/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
-/*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
-`
-
-// takes a *metaData
-const footerTmpl = `/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
-
-/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
-/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
-/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;
-/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
-/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
-/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
-/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
-
--- Dump completed on {{ .CompleteTime }}
-`
-
-// Takes a *table
-const tableTmpl = `
---
--- Table structure for table {{ .NameEsc }}
---
-
-DROP TABLE IF EXISTS {{ .NameEsc }};
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
- SET character_set_client = utf8mb4 ;
-{{ .CreateSQL }};
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table {{ .NameEsc }}
---
-
-LOCK TABLES {{ .NameEsc }} WRITE;
-/*!40000 ALTER TABLE {{ .NameEsc }} DISABLE KEYS */;
-{{ range $value := .Stream }}
-{{- $value }}
-{{ end -}}
-/*!40000 ALTER TABLE {{ .NameEsc }} ENABLE KEYS */;
-UNLOCK TABLES;
-`
+	// Separating comma, terminating semicolon and the command byte of the query packet
+	statementOverhead = 3
+)
 
 const nullType = "NULL"
 
@@ -128,10 +109,6 @@ func (data *Data) Dump() error {
 		DumpVersion: Version,
 	}
 
-	if data.MaxAllowedPacket == 0 {
-		data.MaxAllowedPacket = defaultMaxAllowedPacket
-	}
-
 	if err := data.getTemplates(); err != nil {
 		return err
 	}
@@ -148,8 +125,14 @@ func (data *Data) Dump() error {
 		return err
 	}
 
-	if err := data.headerTmpl.Execute(data.Out, meta); err != nil {
-		return err
+	if data.writesSQL() {
+		if err := data.updateMaxAllowedPacket(); err != nil {
+			return err
+		}
+
+		if err := data.headerTmpl.Execute(data.Out, meta); err != nil {
+			return err
+		}
 	}
 
 	tables, err := data.getTables()
@@ -157,18 +140,16 @@ func (data *Data) Dump() error {
 		return err
 	}
 
-	// Lock all tables before dumping if present
-	if data.LockTables && len(tables) > 0 {
-		var b bytes.Buffer
-		b.WriteString("LOCK TABLES ")
-		for index, name := range tables {
-			if index != 0 {
-				b.WriteString(",")
-			}
-			b.WriteString("`" + name + "` READ /*!32311 LOCAL */")
+	if data.Format == config.FormatSQLite {
+		if err := data.openSQLite(); err != nil {
+			return err
 		}
+		defer data.closeSQLite()
+	}
 
-		if _, err := data.Connection.Exec(b.String()); err != nil {
+	// Lock all tables before dumping if present
+	if lockQuery := data.dialect().LockTablesQuery(tables); data.LockTables && len(tables) > 0 && lockQuery != "" {
+		if _, err := data.Connection.Exec(lockQuery); err != nil {
 			return err
 		}
 
@@ -184,6 +165,11 @@ func (data *Data) Dump() error {
 		return data.err
 	}
 
+	if !data.writesSQL() {
+		return nil
+	}
+
+	meta.DeferredSQL = data.deferred
 	meta.CompleteTime = time.Now().String()
 	return data.footerTmpl.Execute(data.Out, meta)
 }
@@ -212,20 +198,56 @@ func (data *Data) dumpTable(name string) error {
 		return data.err
 	}
 	table := data.createTable(name)
-	return data.writeTable(table)
+	var err error
+	switch {
+	case data.writesSQL():
+		err = data.writeTable(table)
+	case data.Format == config.FormatSQLite:
+		err = data.writeSQLiteTable(table)
+	default:
+		err = data.exportTable(table)
+	}
+	table.warnFakers()
+	if table.excluded > 0 {
+		if data.Excluded == nil {
+			data.Excluded = make(map[string]int64)
+		}
+		data.Excluded[table.Name] = table.excluded
+	}
+	if closeErr := table.closeFakers(); err == nil {
+		err = closeErr
+	}
+	return err
 }
 
 func (data *Data) writeTable(table *table) error {
 	if err := data.tableTmpl.Execute(data.Out, table); err != nil {
 		return err
 	}
+	if table.def != nil {
+		data.deferred = append(data.deferred, table.def.Deferred...)
+	}
 	return table.Err
 }
 
 // MARK: get methods
 
-// getTemplates initializes the templates on data from the constants in this file
+// writesSQL tells whether the dump is a single SQL stream
+func (data *Data) writesSQL() bool {
+	return data.Format == "" || data.Format == config.FormatSQL
+}
+
+// dialect returns the database dialect, MySQL unless set
+func (data *Data) dialect() Dialect {
+	if data.Dialect == nil {
+		return dialects[config.DriverMySQL]
+	}
+	return data.Dialect
+}
+
+// getTemplates initializes the templates on data from the dialect
 func (data *Data) getTemplates() (err error) {
+	headerTmpl, tableTmpl, footerTmpl := data.dialect().Templates()
 	data.headerTmpl, err = template.New("mysqldumpHeader").Parse(headerTmpl)
 	if err != nil {
 		return
@@ -246,7 +268,7 @@ func (data *Data) getTemplates() (err error) {
 func (data *Data) getTables() ([]string, error) {
 	tables := make([]string, 0)
 
-	rows, err := data.tx.Query("SHOW TABLES")
+	rows, err := data.tx.Query(data.dialect().TablesQuery())
 	if err != nil {
 		return tables, err
 	}
@@ -265,6 +287,7 @@ func (data *Data) getTables() ([]string, error) {
 }
 
 var getIsIgnoredtable = config.IsIgnoredTable
+
 func (data *Data) isIgnoredTable(name string) bool {
 	if getIsIgnoredtable(name) {
 		return true
@@ -279,11 +302,36 @@ func (data *Data) isIgnoredTable(name string) bool {
 
 func (meta *metaData) updateServerVersion(data *Data) (err error) {
 	var serverVersion sql.NullString
-	err = data.tx.QueryRow("SELECT version()").Scan(&serverVersion)
+	err = data.tx.QueryRow(data.dialect().ServerVersionQuery()).Scan(&serverVersion)
 	meta.ServerVersion = serverVersion.String
 	return
 }
 
+// updateMaxAllowedPacket reads the packet size limit from the server unless it is set explicitly,
+// batches don't exceed defaultBatchSize then
+func (data *Data) updateMaxAllowedPacket() error {
+	if data.MaxAllowedPacket > 0 {
+		return nil
+	}
+	query := data.dialect().MaxAllowedPacketQuery()
+	if query == "" {
+		data.MaxAllowedPacket = defaultMaxAllowedPacket
+		return nil
+	}
+	var maxAllowedPacket sql.NullInt64
+	if err := data.tx.QueryRow(query).Scan(&maxAllowedPacket); err != nil {
+		return err
+	}
+	data.MaxAllowedPacket = int(maxAllowedPacket.Int64)
+	if data.MaxAllowedPacket <= 0 {
+		data.MaxAllowedPacket = defaultMaxAllowedPacket
+	}
+	if data.MaxAllowedPacket > defaultBatchSize {
+		data.MaxAllowedPacket = defaultBatchSize
+	}
+	return nil
+}
+
 // MARK: create methods
 
 func (data *Data) createTable(name string) *table {
@@ -294,77 +342,128 @@ func (data *Data) createTable(name string) *table {
 }
 
 func (table *table) NameEsc() string {
-	return "`" + table.Name + "`"
+	return table.data.dialect().QuoteIdentifier(table.Name)
+}
+
+// definition reads the table definition once
+func (table *table) definition() (*tableDefinition, error) {
+	if table.def == nil {
+		def, err := table.data.dialect().TableDefinition(table.data.tx, table.Name)
+		if err != nil {
+			return nil, err
+		}
+		table.def = def
+	}
+	return table.def, nil
 }
 
 func (table *table) CreateSQL() (string, error) {
-	var tableReturn, tableSQL sql.NullString
-	if err := table.data.tx.QueryRow("SHOW CREATE TABLE "+table.NameEsc()).Scan(&tableReturn, &tableSQL); err != nil {
+	def, err := table.definition()
+	if err != nil {
 		return "", err
 	}
+	return def.Create, nil
+}
 
-	if tableReturn.String != table.Name {
-		return "", errors.New("Returned table is not the same as requested table")
+// PostDataSQL returns statements to run once the table data is restored
+func (table *table) PostDataSQL() (string, error) {
+	def, err := table.definition()
+	if err != nil {
+		return "", err
 	}
-
-	return tableSQL.String, nil
+	return def.PostData, nil
 }
 
 func (table *table) initColumnData() error {
-	colInfo, err := table.data.tx.Query("SHOW COLUMNS FROM " + table.NameEsc())
+	cols, err := table.data.dialect().Columns(table.data.tx, table.Name)
 	if err != nil {
 		return err
 	}
-	defer colInfo.Close()
 
-	cols, err := colInfo.Columns()
+	maxLengths, err := table.maxLengths()
 	if err != nil {
 		return err
 	}
 
-	fieldIndex, extraIndex := -1, -1
-	for i, col := range cols {
-		switch col {
-		case "Field", "field":
-			fieldIndex = i
-		case "Extra", "extra":
-			extraIndex = i
-		}
-		if fieldIndex >= 0 && extraIndex >= 0 {
-			break
+	var result []string
+	for _, col := range cols {
+		// Ignore the virtual columns
+		if col.Generated {
+			table.generated = append(table.generated, col.Name)
+			continue
 		}
+		col.MaxLength = maxLengths[col.Name]
+		result = append(result, col.Name)
+		table.columns = append(table.columns, col)
 	}
-	if fieldIndex < 0 || extraIndex < 0 {
-		return errors.New("database column information is malformed")
+	table.cols = result
+	return nil
+}
+
+// maxLengths reads CHARACTER_MAXIMUM_LENGTH of the table columns
+func (table *table) maxLengths() (map[string]int, error) {
+	rows, err := table.data.tx.Query(table.data.dialect().MaxLengthQuery(), table.Name)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	info := make([]sql.NullString, len(cols))
-	scans := make([]interface{}, len(cols))
-	for i := range info {
-		scans[i] = &info[i]
+	result := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var maxLength sql.NullInt64
+		if err := rows.Scan(&name, &maxLength); err != nil {
+			return nil, err
+		}
+		// Lengths of LONGTEXT and alike don't fit int on 32-bit platforms, they are not worth limiting anyway
+		if maxLength.Valid && maxLength.Int64 <= math.MaxInt32 {
+			result[name] = int(maxLength.Int64)
+		}
 	}
+	return result, rows.Err()
+}
 
-	var result []string
-	for colInfo.Next() {
-		// Read into the pointers to the info marker
-		if err := colInfo.Scan(scans...); err != nil {
-			return err
+// warnFakers reports the obfuscated columns whose fake values were cut to fit or whose original values could not be parsed
+func (table *table) warnFakers() {
+	for i, generator := range table.colFakers {
+		for ; generator != nil; generator = faker.Unwrap(generator) {
+			if limiter, ok := generator.(*faker.LengthLimiter); ok && limiter.Truncated > 0 {
+				table.data.warn("%s.%s: %d fake values are truncated to %d characters", table.Name, table.cols[i], limiter.Truncated, limiter.MaxLength)
+			}
+			if counter, ok := generator.(faker.InvalidCounter); ok && counter.Invalid() > 0 {
+				table.data.warn("%s.%s: %d original values can't be parsed and are written as NULL", table.Name, table.cols[i], counter.Invalid())
+			}
 		}
+	}
+}
 
-		// Ignore the virtual columns
-		if !info[extraIndex].Valid || !strings.Contains(info[extraIndex].String, "VIRTUAL") {
-			result = append(result, info[fieldIndex].String)
+// closeFakers stops the fakers that hold resources for the table, e.g. external commands
+func (table *table) closeFakers() error {
+	var err error
+	for i, generator := range table.colFakers {
+		for ; generator != nil; generator = faker.Unwrap(generator) {
+			if closer, ok := generator.(io.Closer); ok {
+				if closeErr := closer.Close(); closeErr != nil && err == nil {
+					err = fmt.Errorf("%s.%s: %v", table.Name, table.cols[i], closeErr)
+				}
+			}
 		}
 	}
-	table.cols = result
-	return nil
+	return err
 }
 
 func (table *table) columnsList() string {
-	return "`" + strings.Join(table.cols, "`, `") + "`"
+	quoted := make([]string, len(table.cols))
+	for i, col := range table.cols {
+		quoted[i] = table.data.dialect().QuoteIdentifier(col)
+	}
+	return strings.Join(quoted, ", ")
 }
 
 var shouldDumpData = config.ShouldDumpData
+var getColumnFaker = config.GetColumnFaker
+var getExcludeRule = config.GetExcludeRule
+
 func (table *table) Init() error {
 	if len(table.values) != 0 {
 		return errors.New("can't init twice")
@@ -379,10 +478,28 @@ func (table *table) Init() error {
 		return nil
 	}
 
+	// Fakers sample the rows that are not excluded
+	if shouldDumpData(table.Name) {
+		if err := table.initExclude(); err != nil {
+			return err
+		}
+	}
+
+	// Fakers could query the table, so they are ready before the rows are read
+	if err := table.initFakers(); err != nil {
+		return err
+	}
+
 	var err error
 	// TODO: Dirty! Redo
 	if shouldDumpData(table.Name) {
-		table.rows, err = table.data.tx.Query("SELECT " + table.columnsList() + " FROM " + table.NameEsc())
+		selected := table.columnsList()
+		if table.exclude != nil && table.exclude.SQL != "" {
+			// The predicate is read with the row and the matching rows are dropped in Next
+			selected += ", (" + table.exclude.SQL + ")"
+			table.excludeValue = new(interface{})
+		}
+		table.rows, err = table.data.tx.Query("SELECT " + selected + " FROM " + table.NameEsc())
 		if err != nil {
 			return err
 		}
@@ -395,12 +512,10 @@ func (table *table) Init() error {
 		return err
 	}
 
-	columnNames, _ := table.rows.Columns()
-	table.colFakers = make([]faker.FakeGenerator, len(tt))
-	for i, _ := range tt {
-		table.colFakers[i] = config.GetColumnFaker(table.Name, columnNames[i])
+	if table.excludeValue != nil {
+		tt = tt[:len(tt)-1]
 	}
-
+	table.colTypes = tt
 	table.values = make([]interface{}, len(tt))
 	for i, tp := range tt {
 		table.values[i] = reflect.New(reflectColumnType(tp)).Interface()
@@ -408,6 +523,162 @@ func (table *table) Init() error {
 	return nil
 }
 
+// initExclude reads the exclude rule, its condition could refer to the columns of the row only
+func (table *table) initExclude() error {
+	var err error
+	if table.exclude, err = getExcludeRule(table.Name); err != nil {
+		return fmt.Errorf("%s: the exclude rule is invalid: %v", table.Name, err)
+	}
+	if table.exclude == nil || table.exclude.Condition == nil {
+		return nil
+	}
+	for _, name := range table.exclude.Condition.Columns() {
+		if !contains(table.cols, name) {
+			return fmt.Errorf("%s: the exclude condition refers to unknown column %s", table.Name, name)
+		}
+	}
+	return nil
+}
+
+// initFakers creates the data generators of the obfuscated columns
+func (table *table) initFakers() error {
+	table.colFakers = make([]faker.FakeGenerator, len(table.columns))
+	for i, col := range table.columns {
+		generator := getColumnFaker(table.Name, col.Name)
+		// Conditions are evaluated on the values of the row
+		for _, name := range faker.ConditionColumns(generator) {
+			if !contains(table.cols, name) {
+				return fmt.Errorf("%s.%s: the condition refers to unknown column %s", table.Name, col.Name, name)
+			}
+		}
+		for _, name := range faker.EntityColumns(generator) {
+			if !contains(table.cols, name) {
+				return fmt.Errorf("%s.%s: the entity column %s is unknown", table.Name, col.Name, name)
+			}
+		}
+		if generator != nil {
+			column := fakerColumn(col)
+			if err := faker.CheckColumn(generator, column); err != nil {
+				return fmt.Errorf("%s.%s: %v", table.Name, col.Name, err)
+			}
+			// The DEFAULT keyword has no counterpart in the data files
+			if column.DefaultExpr != "" && !table.data.writesSQL() && usesDefault(generator) {
+				return fmt.Errorf("%s.%s: the default %s is an expression that is written in SQL dumps only, use another type", table.Name, col.Name, column.DefaultExpr)
+			}
+			faker.SetColumn(generator, column)
+		}
+		// Fake values must fit the column in strict SQL mode
+		table.colFakers[i] = faker.NewLengthLimiter(generator, col.MaxLength)
+	}
+	if err := table.initUniqueFakers(); err != nil {
+		return err
+	}
+	table.batchSize = 1
+	for _, generator := range table.colFakers {
+		if batcher := faker.GetBatcher(generator); batcher != nil && batcher.BatchSize() > table.batchSize {
+			table.batchSize = batcher.BatchSize()
+		}
+	}
+	if !shouldDumpData(table.Name) {
+		return nil
+	}
+	return table.sampleFakers()
+}
+
+// usesDefault tells whether the generator or the ones it wraps write the column default
+func usesDefault(generator faker.FakeGenerator) bool {
+	for ; generator != nil; generator = faker.Unwrap(generator) {
+		if _, ok := generator.(*faker.FakeDefault); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleFakers counts the original values of the columns whose fakers follow the real distribution
+func (table *table) sampleFakers() error {
+	for i, generator := range table.colFakers {
+		sampler := faker.GetSampler(generator)
+		if sampler == nil {
+			continue
+		}
+		// Excluded rows are not sampled, the columns of the exclude condition are grouped by to evaluate it
+		columns := []string{table.cols[i]}
+		where := ""
+		if table.exclude != nil {
+			if table.exclude.Condition != nil {
+				columns = append(columns, table.exclude.Condition.Columns()...)
+			} else if table.exclude.SQL != "" {
+				where = " WHERE NOT COALESCE((" + table.exclude.SQL + "), FALSE)"
+			}
+		}
+		quoted := make([]string, len(columns))
+		for j, name := range columns {
+			quoted[j] = table.data.dialect().QuoteIdentifier(name)
+		}
+		list := strings.Join(quoted, ", ")
+		rows, err := table.data.tx.Query("SELECT " + list + ", COUNT(*) FROM " + table.NameEsc() + where + " GROUP BY " + list)
+		if err != nil {
+			return err
+		}
+		var values []interface{}
+		var counts []int64
+		group := make([]interface{}, len(columns))
+		scans := make([]interface{}, len(columns)+1)
+		for j := range group {
+			scans[j] = &group[j]
+		}
+		for rows.Next() {
+			var count int64
+			scans[len(columns)] = &count
+			if err := rows.Scan(scans...); err != nil {
+				rows.Close()
+				return err
+			}
+			row := &faker.Row{Table: table.Name, Values: make(map[string]interface{}, len(columns))}
+			for j, value := range group {
+				if b, ok := value.([]byte); ok {
+					group[j] = string(b)
+				}
+				row.Values[columns[j]] = group[j]
+			}
+			if table.exclude != nil && table.exclude.Condition != nil && table.exclude.Condition.Matches(row) {
+				continue
+			}
+			values = append(values, group[0])
+			counts = append(counts, count)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		sampler.SetSample(values, counts)
+	}
+	return nil
+}
+
+// initUniqueFakers makes fakers of the columns under unique indexes never repeat a value
+func (table *table) initUniqueFakers() error {
+	obfuscated := false
+	for _, generator := range table.colFakers {
+		obfuscated = obfuscated || generator != nil
+	}
+	if !obfuscated {
+		return nil
+	}
+
+	unique, err := table.data.dialect().UniqueColumns(table.data.tx, table.Name)
+	if err != nil {
+		return err
+	}
+	for i, generator := range table.colFakers {
+		if unique[table.cols[i]] {
+			table.colFakers[i] = faker.NewUniqueGenerator(generator)
+		}
+	}
+	return nil
+}
+
 func reflectColumnType(tp *sql.ColumnType) reflect.Type {
 	// reflect for scanable
 	switch tp.ScanType().Kind() {
@@ -417,24 +688,32 @@ func reflectColumnType(tp *sql.ColumnType) reflect.Type {
 		return reflect.TypeOf(sql.NullFloat64{})
 	case reflect.String:
 		return reflect.TypeOf(sql.NullString{})
+	case reflect.Bool:
+		return reflect.TypeOf(sql.NullBool{})
 	}
 
 	// determine by name
 	switch tp.DatabaseTypeName() {
-	case "BLOB", "BINARY", "VARBINARY":
+	case "BLOB", "BINARY", "VARBINARY", "BYTEA":
 		return reflect.TypeOf(sql.RawBytes{})
-	case "VARCHAR", "TEXT", "DECIMAL", "JSON", "TIMESTAMP", "DATETIME", "DATE":
+	case "VARCHAR", "TEXT", "DECIMAL", "JSON", "TIMESTAMP", "DATETIME", "DATE",
+		"BPCHAR", "NUMERIC", "JSONB", "UUID", "TIMESTAMPTZ", "TIME", "TIMETZ", "INTERVAL":
 		return reflect.TypeOf(sql.NullString{})
-	case "BIGINT", "SMALLINT", "TINYINT", "INT":
+	case "BIGINT", "SMALLINT", "TINYINT", "INT", "INT2", "INT4", "INT8":
 		return reflect.TypeOf(sql.NullInt64{})
-	case "DOUBLE", "FLOAT":
+	case "DOUBLE", "FLOAT", "FLOAT4", "FLOAT8":
 		return reflect.TypeOf(sql.NullFloat64{})
+	case "BOOL":
+		return reflect.TypeOf(sql.NullBool{})
 	default:
-		fmt.Println("Field", tp, " unknown type: ", tp.DatabaseTypeName())
+		fmt.Println("Field", tp.Name(), " unknown type: ", tp.DatabaseTypeName())
 	}
 
-	// unknown datatype
-	return tp.ScanType()
+	// unknown datatype, e.g. PostgreSQL arrays, inet or citext, is read as its text or bytes
+	if scanType := tp.ScanType(); scanType != nil && scanType.Kind() == reflect.Slice && scanType.Elem().Kind() == reflect.Uint8 {
+		return reflect.TypeOf(sql.RawBytes{})
+	}
+	return reflect.TypeOf(sql.NullString{})
 }
 
 func (table *table) Next() bool {
@@ -446,73 +725,297 @@ func (table *table) Next() bool {
 		}
 	}
 	// Fallthrough
-	if table.rows.Next() {
-		if err := table.rows.Scan(table.values...); err != nil {
-			table.Err = err
-			fmt.Println(err)
-			return false
-		} else if err := table.rows.Err(); err != nil {
+	if len(table.pending) == 0 {
+		if err := table.readAhead(); err != nil {
 			table.Err = err
 			fmt.Println(err)
 			return false
 		}
-	} else {
+	}
+	if len(table.pending) == 0 {
 		table.rows.Close()
 		table.rows = nil
 		return false
 	}
+	table.rowNumber++
+	table.row = table.RowData(table.pending[0])
+	table.pending = table.pending[1:]
+	if err := table.fakerErr(); err != nil {
+		table.Err = err
+		fmt.Println(err)
+		return false
+	}
 	return true
 }
 
-func (table *table) RowValues() string {
-	return table.RowBuffer().String()
+// readAhead reads the next rows, as many as the fakers transform in a batch, and prepares the batches
+func (table *table) readAhead() error {
+	targets := table.values
+	if table.excludeValue != nil {
+		targets = append(append([]interface{}{}, table.values...), table.excludeValue)
+	}
+	for len(table.pending) < table.batchSize && table.rows.Next() {
+		if err := table.rows.Scan(targets...); err != nil {
+			return err
+		}
+		row := table.originalValues()
+		// Excluded rows never reach the fakers
+		if table.isExcluded(row) {
+			table.excluded++
+			continue
+		}
+		table.pending = append(table.pending, row)
+	}
+	if err := table.rows.Err(); err != nil {
+		return err
+	}
+	if len(table.pending) == 0 {
+		return nil
+	}
+
+	for i, generator := range table.colFakers {
+		batcher := faker.GetBatcher(generator)
+		if batcher == nil {
+			continue
+		}
+		originals := make([]interface{}, len(table.pending))
+		for j, row := range table.pending {
+			originals[j] = row[i]
+		}
+		if err := batcher.Prepare(table.Name, originals); err != nil {
+			return fmt.Errorf("%s.%s: %v", table.Name, table.cols[i], err)
+		}
+	}
+	return nil
 }
 
-func (table *table) RowBuffer() *bytes.Buffer {
-	var b bytes.Buffer
-	b.WriteString("(")
+// isExcluded tells whether the exclude rule drops the row, NULL of the predicate keeps it like in a WHERE clause
+func (table *table) isExcluded(row []interface{}) bool {
+	switch {
+	case table.exclude == nil:
+		return false
+	case table.exclude.Condition != nil:
+		return table.exclude.Condition.Matches(table.originalRow(row))
+	case table.excludeValue == nil:
+		return false
+	}
+	switch v := (*table.excludeValue).(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case []byte:
+		return isTrueString(string(v))
+	case string:
+		return isTrueString(v)
+	}
+	return false
+}
 
-	for key, value := range table.values {
-		if key != 0 {
-			b.WriteString(",")
+// isTrueString reads a boolean the databases write as text
+func isTrueString(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "0", "f", "false":
+		return false
+	}
+	if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+		return f != 0
+	}
+	return true
+}
+
+// fakerColumn describes the column to its generator
+func fakerColumn(col column) faker.Column {
+	column := faker.Column{
+		Name:      col.Name,
+		Type:      col.Type,
+		MaxLength: col.MaxLength,
+		Nullable:  col.Nullable,
+	}
+	if !col.Default.Valid {
+		return column
+	}
+	if !col.DefaultExpr {
+		value := col.Default.String
+		column.Default = &value
+		return column
+	}
+	if value, isNull, ok := defaultLiteral(col.Default.String); ok {
+		if !isNull {
+			column.Default = &value
 		}
-		// Point of impact
-		if table.colFakers[key] != nil {
-			newValue := table.colFakers[key].GetData()
-			if _, ok := newValue.(string); ok {
-				//newValue = strings.Replace(newValue.(string), "'", "\\'", -1)
-				newValue = sanitize(newValue.(string))
+		return column
+	}
+	column.DefaultExpr = col.Default.String
+	return column
+}
+
+// defaultLiteral reads the value of a default expression that is a literal,
+// e.g. 'new'::character varying, (-1), 0.5::numeric or NULL::text
+func defaultLiteral(expr string) (value string, isNull bool, ok bool) {
+	expr = strings.TrimSpace(expr)
+	for len(expr) > 1 && expr[0] == '(' && expr[len(expr)-1] == ')' {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	if strings.HasPrefix(expr, "'") {
+		end := strings.LastIndex(expr, "'")
+		if end == 0 {
+			return "", false, false
+		}
+		rest := expr[end+1:]
+		if rest != "" && !strings.HasPrefix(rest, "::") {
+			return "", false, false
+		}
+		quoted := expr[1:end]
+		if strings.Contains(strings.ReplaceAll(quoted, "''", ""), "'") {
+			return "", false, false
+		}
+		return strings.ReplaceAll(quoted, "''", "'"), false, true
+	}
+	if i := strings.Index(expr, "::"); i >= 0 {
+		expr = expr[:i]
+	}
+	for len(expr) > 1 && expr[0] == '(' && expr[len(expr)-1] == ')' {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	switch strings.ToLower(expr) {
+	case "null":
+		return "", true, true
+	case "true", "false":
+		return strings.ToLower(expr), false, true
+	}
+	if _, err := strconv.ParseFloat(expr, 64); err == nil {
+		return expr, false, true
+	}
+	return "", false, false
+}
+
+// contains tells whether the name is in the list
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fakerErr returns the first error of the generators that failed to produce a value
+func (table *table) fakerErr() error {
+	for i, generator := range table.colFakers {
+		for ; generator != nil; generator = faker.Unwrap(generator) {
+			if failing, ok := generator.(faker.Failing); ok && failing.Err() != nil {
+				return fmt.Errorf("%s.%s: %v", table.Name, table.cols[i], failing.Err())
 			}
-			value = newValue
 		}
+	}
+	return nil
+}
 
+func (table *table) RowValues() string {
+	return table.RowBuffer().String()
+}
+
+// originalValues returns the scanned values of the current row
+func (table *table) originalValues() []interface{} {
+	row := make([]interface{}, len(table.values))
+	for key, value := range table.values {
 		switch s := value.(type) {
-		case nil:
-			b.WriteString(nullType)
 		case *sql.NullString:
 			if s.Valid {
-				fmt.Fprintf(&b, "'%s'", sanitize(s.String))
-			} else {
-				b.WriteString(nullType)
+				row[key] = s.String
 			}
 		case *sql.NullInt64:
 			if s.Valid {
-				fmt.Fprintf(&b, "%d", s.Int64)
-			} else {
-				b.WriteString(nullType)
+				row[key] = s.Int64
 			}
 		case *sql.NullFloat64:
 			if s.Valid {
-				fmt.Fprintf(&b, "%f", s.Float64)
-			} else {
-				b.WriteString(nullType)
+				row[key] = s.Float64
+			}
+		case *sql.NullBool:
+			if s.Valid {
+				row[key] = s.Bool
 			}
 		case *sql.RawBytes:
-			if len(*s) == 0 {
-				b.WriteString(nullType)
+			if len(*s) != 0 {
+				// RawBytes are reused by the next Scan
+				row[key] = append([]byte{}, *s...)
+			}
+		default:
+			// The scan target is reused by the next Scan, so the value is copied
+			if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && !v.IsNil() {
+				row[key] = v.Elem().Interface()
+			} else {
+				row[key] = value
+			}
+		}
+	}
+	return row
+}
+
+// RowData returns values of the row with the fake data in place of the obfuscated columns
+func (table *table) RowData(row []interface{}) []interface{} {
+	// Generators could depend on any original value of the row
+	var original *faker.Row
+	for key, generator := range table.colFakers {
+		if generator == nil {
+			continue
+		}
+		if original == nil {
+			original = table.originalRow(row)
+		}
+		// Point of impact
+		row[key] = faker.FakeRow(generator, original.Values[table.cols[key]], original)
+	}
+	return row
+}
+
+// originalRow keeps the original values of the row for the generators
+func (table *table) originalRow(row []interface{}) *faker.Row {
+	original := &faker.Row{
+		Table:  table.Name,
+		Number: table.rowNumber,
+		Values: make(map[string]interface{}, len(row)),
+	}
+	for key, value := range row {
+		original.Values[table.cols[key]] = value
+	}
+	return original
+}
+
+func (table *table) RowBuffer() *bytes.Buffer {
+	var b bytes.Buffer
+	b.WriteString("(")
+
+	for key, value := range table.row {
+		if key != 0 {
+			b.WriteString(",")
+		}
+
+		switch s := value.(type) {
+		case nil:
+			b.WriteString(nullType)
+		case string:
+			b.WriteString(table.data.dialect().QuoteString(s))
+		case int64:
+			fmt.Fprintf(&b, "%d", s)
+		case float64:
+			fmt.Fprintf(&b, "%f", s)
+		case bool:
+			if s {
+				b.WriteString("TRUE")
 			} else {
-				fmt.Fprintf(&b, "_binary '%s'", sanitize(string(*s)))
+				b.WriteString("FALSE")
 			}
+		case []byte:
+			b.WriteString(table.data.dialect().QuoteBinary(s))
+		case faker.Decimal:
+			b.WriteString(string(s))
+		case faker.DefaultKeyword:
+			b.WriteString("DEFAULT")
 		default:
 			fmt.Fprintf(&b, "'%s'", value)
 		}
@@ -531,7 +1034,7 @@ func (table *table) Stream() <-chan string {
 		for table.Next() {
 			b := table.RowBuffer()
 			// Truncate our insert if it won't fit
-			if insert.Len() != 0 && insert.Len()+b.Len() > table.data.MaxAllowedPacket-1 {
+			if insert.Len() != 0 && insert.Len()+b.Len()+statementOverhead > table.data.MaxAllowedPacket {
 				insert.WriteString(";")
 				valueOut <- insert.String()
 				insert.Reset()
@@ -3,6 +3,7 @@ package mysqldump
 import (
 	"bytes"
 	"database/sql"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/vicdeo/go-obfuscate/config"
+	"github.com/vicdeo/go-obfuscate/faker"
 )
 
 func getMockData() (data *Data, mock sqlmock.Sqlmock, err error) {
@@ -169,6 +171,33 @@ func TestCreateTableSQLOk(t *testing.T) {
 	}
 }
 
+// mockMaxLengths mocks the column length query, lengths go in column name and length pairs
+func mockMaxLengths(mock sqlmock.Sqlmock, name string, lengths ...interface{}) {
+	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "CHARACTER_MAXIMUM_LENGTH"})
+	for i := 0; i+1 < len(lengths); i += 2 {
+		rows.AddRow(lengths[i], lengths[i+1])
+	}
+	mock.ExpectQuery("(?i)FROM information_schema.COLUMNS").WithArgs(name).WillReturnRows(rows)
+}
+
+// mockMariaDBDefaults mocks the defaults of MariaDB as pairs of column name and COLUMN_DEFAULT, none are returned by MySQL
+func mockMariaDBDefaults(mock sqlmock.Sqlmock, name string, defaults ...interface{}) {
+	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_DEFAULT"})
+	for i := 0; i+1 < len(defaults); i += 2 {
+		rows.AddRow(defaults[i], defaults[i+1])
+	}
+	mock.ExpectQuery("(?i)COLUMN_DEFAULT FROM information_schema.COLUMNS").WithArgs(name).WillReturnRows(rows)
+}
+
+// mockUniqueColumns mocks SHOW INDEX with a single column unique index per column
+func mockUniqueColumns(mock sqlmock.Sqlmock, name string, columns ...string) {
+	rows := sqlmock.NewRows([]string{"Table", "Non_unique", "Key_name", "Seq_in_index", "Column_name"})
+	for _, column := range columns {
+		rows.AddRow(name, 0, column+"_unique", 1, column)
+	}
+	mock.ExpectQuery("^SHOW INDEX FROM `" + name + "`$").WillReturnRows(rows)
+}
+
 func mockTableSelect(mock sqlmock.Sqlmock, name string) {
 	cols := sqlmock.NewRows([]string{"Field", "Extra"}).
 		AddRow("id", "").
@@ -180,6 +209,7 @@ func mockTableSelect(mock sqlmock.Sqlmock, name string) {
 		AddRow(2, "test2@test.de", "Test Name 2")
 
 	mock.ExpectQuery("^SHOW COLUMNS FROM `" + name + "`$").WillReturnRows(cols)
+	mockMaxLengths(mock, name)
 	mock.ExpectQuery("^SELECT (.+) FROM `" + name + "`$").WillReturnRows(rows)
 }
 
@@ -270,6 +300,44 @@ func TestCreateTableValuesSteamSmallPackets(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
 }
 
+func TestCreateTableValuesSteamPacketBoundary(t *testing.T) {
+	defer func() {
+		getIsIgnoredtable = config.IsIgnoredTable
+		shouldDumpData = config.ShouldDumpData
+	}()
+	getIsIgnoredtable = func(tableName string) bool {
+		return false
+	}
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+
+	first := "INSERT INTO `test` (`id`, `email`, `name`) VALUES (1,'test@test.de','Test Name 1')"
+	second := "(2,'test2@test.de','Test Name 2')"
+	joined := first + "," + second + ";"
+	// The packet also holds the command byte, so the statement fits with a limit one byte larger than it
+	for _, testcase := range []struct {
+		maxAllowedPacket int
+		expected         []string
+	}{
+		{len(joined) + 1, []string{joined}},
+		{len(joined), []string{first + ";", "INSERT INTO `test` (`id`, `email`, `name`) VALUES " + second + ";"}},
+	} {
+		data, mock, err := getMockData()
+		assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+		mockTableSelect(mock, "test")
+		data.MaxAllowedPacket = testcase.maxAllowedPacket
+
+		statements := make([]string, 0)
+		for statement := range data.createTable("test").Stream() {
+			statements = append(statements, statement)
+		}
+		assert.Equal(t, testcase.expected, statements, testcase.maxAllowedPacket)
+		assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+		data.Close()
+	}
+}
+
 func TestCreateTableAllValuesWithNil(t *testing.T) {
 	data, mock, err := getMockData()
 	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
@@ -296,6 +364,7 @@ func TestCreateTableAllValuesWithNil(t *testing.T) {
 		AddRow(3, "", "Test Name 3")
 
 	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
 	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(rows)
 
 	table := data.createTable("test")
@@ -315,6 +384,707 @@ func TestCreateTableAllValuesWithNil(t *testing.T) {
 	assert.EqualValues(t, expectedResults, results)
 }
 
+func TestFakeValuesFitColumnLength(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		if columnName == "name" {
+			return &faker.FakeFixed{Value: "Test Name"}
+		}
+		return nil
+	}
+
+	cols := sqlmock.NewRows([]string{"Field", "Extra"}).
+		AddRow("id", "").
+		AddRow("name", "")
+
+	rows := sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("name", "")).
+		AddRow(1, "Test Name 1").
+		AddRow(2, "Test Name 2")
+
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test", "id", nil, "name", 4)
+	mockUniqueColumns(mock, "test")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(rows)
+
+	table := data.createTable("test")
+
+	results := make([]string, 0)
+	for table.Next() {
+		results = append(results, table.RowValues())
+	}
+	table.warnFakers()
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.EqualValues(t, []string{"(1,'Test')", "(2,'Test')"}, results)
+	assert.Equal(t, []string{"test.name: 2 fake values are truncated to 4 characters"}, data.Warnings)
+}
+
+func TestInvalidOriginalValuesAreReported(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		if columnName == "profile" {
+			return faker.New(map[string]interface{}{"type": "json", "paths": []interface{}{
+				map[string]interface{}{"path": "email", "type": "fixed", "string": "fake@test.de"},
+			}})
+		}
+		return nil
+	}
+
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Extra"}).AddRow("id", "").AddRow("profile", ""))
+	mockMaxLengths(mock, "test")
+	mockUniqueColumns(mock, "test")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(
+		sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("profile", "")).
+			AddRow(1, `{"email": "john@doe.com"}`).
+			AddRow(2, `{"email": `))
+
+	table := data.createTable("test")
+	results := make([]string, 0)
+	for table.Next() {
+		results = append(results, table.RowValues())
+	}
+	table.warnFakers()
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.EqualValues(t, []string{`(1,'{\"email\":\"fake@test.de\"}')`, "(2,NULL)"}, results)
+	assert.Equal(t, []string{"test.profile: 1 original values can't be parsed and are written as NULL"}, data.Warnings)
+}
+
+func TestNumberFakersFollowColumnType(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		return faker.New(map[string]interface{}{"type": "round", "step": 10, "mode": "floor"})
+	}
+
+	cols := sqlmock.NewRows([]string{"Field", "Type", "Extra"}).
+		AddRow("age", "int(11)", "").
+		AddRow("salary", "decimal(10,2)", "")
+
+	rows := sqlmock.NewRowsWithColumnDefinition(c("age", 0), c("salary", "")).
+		AddRow(37, "4321.99").
+		AddRow(nil, nil)
+
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
+	mockUniqueColumns(mock, "test")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(rows)
+
+	table := data.createTable("test")
+
+	results := make([]string, 0)
+	for table.Next() {
+		results = append(results, table.RowValues())
+	}
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.EqualValues(t, []string{"(30,4320.00)", "(NULL,NULL)"}, results)
+}
+
+func TestChoiceFakerSamplesDistribution(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		if columnName == "status" {
+			return faker.New(map[string]interface{}{"type": "choice", "sample": true})
+		}
+		return nil
+	}
+
+	cols := sqlmock.NewRows([]string{"Field", "Extra"}).
+		AddRow("id", "").
+		AddRow("status", "")
+
+	rows := sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("status", "")).
+		AddRow(1, "new").
+		AddRow(2, "new")
+
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
+	mockUniqueColumns(mock, "test")
+	mock.ExpectQuery("^SELECT `status`, COUNT\\(\\*\\) FROM `test` GROUP BY `status`$").WillReturnRows(
+		sqlmock.NewRows([]string{"status", "COUNT(*)"}).AddRow([]byte("shipped"), 5))
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(rows)
+
+	table := data.createTable("test")
+
+	results := make([]string, 0)
+	for table.Next() {
+		results = append(results, table.RowValues())
+	}
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.EqualValues(t, []string{"(1,'shipped')", "(2,'shipped')"}, results)
+}
+
+func TestChoiceFakerSamplesRowsThatAreNotExcluded(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+		getExcludeRule = config.GetExcludeRule
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		if columnName == "status" {
+			return faker.New(map[string]interface{}{"type": "choice", "sample": true})
+		}
+		return nil
+	}
+	condition, _ := faker.ParseCondition("gdpr_deleted = 1")
+	getExcludeRule = func(tableName string) (*config.ExcludeRule, error) {
+		if tableName == "users" {
+			return &config.ExcludeRule{Condition: condition}, nil
+		}
+		return &config.ExcludeRule{SQL: "user_id IN (SELECT id FROM users WHERE gdpr_deleted = 1)"}, nil
+	}
+
+	// The condition is evaluated on the groups
+	mock.ExpectQuery("^SHOW COLUMNS FROM `users`$").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Extra"}).AddRow("status", "").AddRow("gdpr_deleted", ""))
+	mockMaxLengths(mock, "users")
+	mockUniqueColumns(mock, "users")
+	mock.ExpectQuery("^SELECT `status`, `gdpr_deleted`, COUNT\\(\\*\\) FROM `users` GROUP BY `status`, `gdpr_deleted`$").WillReturnRows(
+		sqlmock.NewRows([]string{"status", "gdpr_deleted", "COUNT(*)"}).
+			AddRow([]byte("deleted"), int64(1), 5).
+			AddRow([]byte("active"), int64(0), 2))
+	mock.ExpectQuery("^SELECT (.+) FROM `users`$").WillReturnRows(
+		sqlmock.NewRowsWithColumnDefinition(c("status", ""), c("gdpr_deleted", 0)).AddRow("deleted", 0))
+
+	table := data.createTable("users")
+	assert.True(t, table.Next())
+	assert.Equal(t, "('active',0)", table.RowValues())
+
+	// The predicate filters the rows
+	mock.ExpectQuery("^SHOW COLUMNS FROM `orders`$").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Extra"}).AddRow("status", ""))
+	mockMaxLengths(mock, "orders")
+	mockUniqueColumns(mock, "orders")
+	mock.ExpectQuery("^SELECT `status`, COUNT\\(\\*\\) FROM `orders` WHERE NOT COALESCE\\(\\(user_id IN \\(SELECT id FROM users WHERE gdpr_deleted = 1\\)\\), FALSE\\) GROUP BY `status`$").WillReturnRows(
+		sqlmock.NewRows([]string{"status", "COUNT(*)"}).AddRow([]byte("shipped"), 3))
+	mock.ExpectQuery("^SELECT (.+) FROM `orders`$").WillReturnRows(
+		sqlmock.NewRowsWithColumnDefinition(c("status", ""), c("excluded", 0)).AddRow("new", 0))
+
+	table = data.createTable("orders")
+	assert.True(t, table.Next())
+	assert.Equal(t, "('shipped')", table.RowValues())
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+}
+
+func TestUniqueFakerExhausted(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		if columnName == "email" {
+			return &faker.FakeFixed{Value: "test@test.de"}
+		}
+		return nil
+	}
+
+	cols := sqlmock.NewRows([]string{"Field", "Extra"}).
+		AddRow("id", "").
+		AddRow("email", "")
+
+	rows := sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("email", "")).
+		AddRow(1, "test1@test.de").
+		AddRow(2, "test2@test.de")
+
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
+	mockUniqueColumns(mock, "test", "id", "email")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(rows)
+
+	table := data.createTable("test")
+
+	results := make([]string, 0)
+	for table.Next() {
+		results = append(results, table.RowValues())
+	}
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.EqualValues(t, []string{"(1,'test@test.de')"}, results)
+	assert.EqualError(t, table.Err, "test.email: no unique value is found in 100 attempts after 1 values, the generator is exhausted")
+}
+
+func TestExternalFakerBatches(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	// cat replies the values it is given
+	command := "cat"
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		if columnName == "email" {
+			return faker.New(map[string]interface{}{"type": "external", "command": command, "batch": 2})
+		}
+		return nil
+	}
+
+	cols := sqlmock.NewRows([]string{"Field", "Extra"}).
+		AddRow("id", "").
+		AddRow("email", "")
+
+	rows := sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("email", "")).
+		AddRow(1, "test1@test.de").
+		AddRow(2, nil).
+		AddRow(3, "test3@test.de")
+
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
+	mockUniqueColumns(mock, "test")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(rows)
+
+	table := data.createTable("test")
+
+	results := make([]string, 0)
+	for table.Next() {
+		results = append(results, table.RowValues())
+	}
+	assert.NoError(t, table.closeFakers())
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.NoError(t, table.Err)
+	assert.EqualValues(t, []string{"(1,'test1@test.de')", "(2,NULL)", "(3,'test3@test.de')"}, results)
+
+	// A command that exits without a reply stops the table
+	command = "true"
+	cols = sqlmock.NewRows([]string{"Field", "Extra"}).
+		AddRow("id", "").
+		AddRow("email", "")
+	rows = sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("email", "")).
+		AddRow(1, "test1@test.de")
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
+	mockUniqueColumns(mock, "test")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(rows)
+
+	table = data.createTable("test")
+	assert.False(t, table.Next())
+	if assert.Error(t, table.Err) {
+		// The command is gone either before the values are written or before the reply is read
+		assert.True(t, strings.HasPrefix(table.Err.Error(), "test.email: command true "), table.Err.Error())
+	}
+}
+
+func TestConditionalFakerKeepsTestAccounts(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	condition := "email LIKE '%@ourcompany.com' OR is_staff = 1"
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		if columnName == "email" {
+			unless, _ := faker.ParseCondition(condition)
+			return faker.NewConditionalGenerator(&faker.FakeFixed{Value: "fake@test.de"}, nil, unless)
+		}
+		return nil
+	}
+
+	mockConditionTable := func() {
+		cols := sqlmock.NewRows([]string{"Field", "Extra"}).
+			AddRow("id", "").
+			AddRow("email", "").
+			AddRow("is_staff", "")
+		mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+		mockMaxLengths(mock, "test")
+	}
+
+	mockConditionTable()
+	mockUniqueColumns(mock, "test")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(
+		sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("email", ""), c("is_staff", 0)).
+			AddRow(1, "john@doe.com", 0).
+			AddRow(2, "qa@ourcompany.com", 0).
+			AddRow(3, "boss@doe.com", 1))
+
+	table := data.createTable("test")
+	results := make([]string, 0)
+	for table.Next() {
+		results = append(results, table.RowValues())
+	}
+	assert.NoError(t, table.Err)
+	assert.EqualValues(t, []string{"(1,'fake@test.de',0)", "(2,'qa@ourcompany.com',0)", "(3,'boss@doe.com',1)"}, results)
+
+	// A typo in the condition stops the table before the rows are read
+	condition = "is_staf = 1"
+	mockConditionTable()
+	table = data.createTable("test")
+	assert.False(t, table.Next())
+	assert.EqualError(t, table.Err, "test.email: the condition refers to unknown column is_staf")
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+}
+
+func TestOriginalValuesAreCopied(t *testing.T) {
+	var scanned int64 = 1
+	table := &table{values: []interface{}{&scanned}}
+	row := table.originalValues()
+	scanned = 2
+	assert.Equal(t, []interface{}{int64(1)}, row)
+}
+
+func TestDateShiftEntityIsChecked(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		if columnName == "created_at" {
+			return faker.New(map[string]interface{}{"type": "date_shift", "days": 10, "entity": "user"})
+		}
+		return nil
+	}
+
+	cols := sqlmock.NewRows([]string{"Field", "Extra"}).
+		AddRow("user_id", "").
+		AddRow("created_at", "")
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
+
+	// A typo in the entity column stops the table before the rows are read
+	table := data.createTable("test")
+	assert.False(t, table.Next())
+	assert.EqualError(t, table.Err, "test.created_at: the entity column user is unknown")
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+}
+
+func TestExcludeRuleDropsRows(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getExcludeRule = config.GetExcludeRule
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	condition, _ := faker.ParseCondition("gdpr_deleted = 1")
+	getExcludeRule = func(tableName string) (*config.ExcludeRule, error) {
+		switch tableName {
+		case "users":
+			return &config.ExcludeRule{Condition: condition}, nil
+		case "orders":
+			return &config.ExcludeRule{SQL: "user_id IN (SELECT id FROM users WHERE gdpr_deleted = 1)"}, nil
+		case "invoices":
+			return nil, errors.New("condition is invalid")
+		}
+		return nil, nil
+	}
+
+	mock.ExpectQuery("^SHOW COLUMNS FROM `users`$").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Extra"}).AddRow("id", "").AddRow("gdpr_deleted", ""))
+	mockMaxLengths(mock, "users")
+	mock.ExpectQuery("^SELECT `id`, `gdpr_deleted` FROM `users`$").WillReturnRows(
+		sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("gdpr_deleted", 0)).
+			AddRow(1, 0).
+			AddRow(2, 1).
+			AddRow(3, nil))
+
+	mock.ExpectQuery("^SHOW COLUMNS FROM `orders`$").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Extra"}).AddRow("id", "").AddRow("user_id", ""))
+	mockMaxLengths(mock, "orders")
+	mock.ExpectQuery(`^SELECT ` + "`id`, `user_id`" + `, \(user_id IN \(SELECT id FROM users WHERE gdpr_deleted = 1\)\) FROM ` + "`orders`$").WillReturnRows(
+		sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("user_id", 0), c("excluded", 0)).
+			AddRow(10, 1, 0).
+			AddRow(11, 2, 1).
+			AddRow(12, 2, 1).
+			AddRow(13, nil, nil))
+
+	for _, testcase := range []struct {
+		name     string
+		expected []string
+		excluded int64
+	}{
+		{"users", []string{"(1,0)", "(3,NULL)"}, 1},
+		{"orders", []string{"(10,1)", "(13,NULL)"}, 2},
+	} {
+		table := data.createTable(testcase.name)
+		results := make([]string, 0)
+		for table.Next() {
+			results = append(results, table.RowValues())
+		}
+		assert.NoError(t, table.Err)
+		assert.EqualValues(t, testcase.expected, results)
+		assert.Equal(t, int64(len(testcase.expected)), table.rowNumber)
+		assert.Equal(t, testcase.excluded, table.excluded)
+	}
+
+	// An invalid rule stops the table instead of dumping the rows it was meant to drop
+	mock.ExpectQuery("^SHOW COLUMNS FROM `invoices`$").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Extra"}).AddRow("id", ""))
+	mockMaxLengths(mock, "invoices")
+	table := data.createTable("invoices")
+	assert.False(t, table.Next())
+	assert.EqualError(t, table.Err, "invoices: the exclude rule is invalid: condition is invalid")
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+}
+
+func TestNullAndDefaultStrategies(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		switch columnName {
+		case "token":
+			return &faker.FakeNull{}
+		case "status", "score", "created_at":
+			return &faker.FakeDefault{}
+		}
+		return nil
+	}
+
+	cols := sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+		AddRow("id", "int", "NO", "PRI", nil, "").
+		AddRow("token", "varchar(64)", "YES", "", nil, "").
+		AddRow("status", "varchar(16)", "NO", "", "new", "").
+		AddRow("score", "int", "NO", "", "0", "").
+		AddRow("created_at", "datetime", "NO", "", "CURRENT_TIMESTAMP", "DEFAULT_GENERATED")
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMariaDBDefaults(mock, "test")
+	mockMaxLengths(mock, "test")
+	mockUniqueColumns(mock, "test")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(
+		sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("token", ""), c("status", ""), c("score", 0), c("created_at", "")).
+			AddRow(1, "a1b2", "active", 42, "2020-01-01 00:00:00"))
+
+	table := data.createTable("test")
+	assert.True(t, table.Next())
+	assert.NoError(t, table.Err)
+	// The default expression is evaluated by the server that loads the dump
+	assert.Equal(t, "(1,NULL,'new',0,DEFAULT)", table.RowValues())
+
+	// null can't be configured for a NOT NULL column
+	cols = sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+		AddRow("id", "int", "NO", "PRI", nil, "").
+		AddRow("token", "varchar(64)", "NO", "", nil, "")
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
+	table = data.createTable("test")
+	assert.False(t, table.Next())
+	assert.EqualError(t, table.Err, "test.token: the column is NOT NULL, use default or another type")
+}
+
+func TestCheckColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		db.Close()
+		getColumnFaker = config.GetColumnFaker
+	}()
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		switch columnName {
+		case "token":
+			return &faker.FakeNull{}
+		case "created_at", "note":
+			return &faker.FakeDefault{}
+		}
+		return &faker.FakeFixed{Value: "x"}
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SHOW COLUMNS FROM `users`$").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("id", "int", "NO", "PRI", nil, "").
+			AddRow("email", "varchar(255)", "NO", "", nil, "").
+			AddRow("token", "varchar(64)", "NO", "", nil, "").
+			AddRow("note", "varchar(64)", "NO", "", nil, "").
+			AddRow("created_at", "datetime", "NO", "", "CURRENT_TIMESTAMP", "DEFAULT_GENERATED"))
+	mock.ExpectRollback()
+
+	dialect, _ := NewDialect("mysql")
+	messages, err := CheckColumns(db, dialect, map[string][]string{"users": {"created_at", "email", "emial", "note", "token"}})
+	assert.NoError(t, err)
+	// A default expression is written as DEFAULT
+	assert.Equal(t, [][]string{
+		{"users", "emial", "column is not found"},
+		{"users", "note", "the column is NOT NULL without a default, use another type"},
+		{"users", "token", "the column is NOT NULL, use default or another type"},
+	}, messages)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMysqlDefaultExpressions(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer data.Close()
+
+	showColumns := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("id", "int", "NO", "PRI", nil, "").
+			AddRow("score", "int", "NO", "", "0", "").
+			// MySQL 8.0 marks the expressions
+			AddRow("created_at", "datetime", "NO", "", "CURRENT_TIMESTAMP", "DEFAULT_GENERATED").
+			// MySQL 5.7 allows CURRENT_TIMESTAMP of the temporal columns only, MariaDB shows it as a call
+			AddRow("updated_at", "timestamp", "NO", "", "CURRENT_TIMESTAMP", "on update CURRENT_TIMESTAMP").
+			AddRow("seen_at", "timestamp", "NO", "", "current_timestamp()", "on update current_timestamp()").
+			// Literals or expressions, depending on the quoting of MariaDB
+			AddRow("uid", "char(36)", "NO", "", "uuid()", "").
+			AddRow("state", "varchar(32)", "NO", "", "CURRENT_TIMESTAMP", "")
+	}
+	expressions := func() map[string]bool {
+		cols, err := data.dialect().Columns(data.tx, "users")
+		assert.NoError(t, err)
+		result := make(map[string]bool, len(cols))
+		for _, col := range cols {
+			result[col.Name] = col.DefaultExpr
+		}
+		return result
+	}
+
+	// MySQL
+	mock.ExpectQuery("^SHOW COLUMNS FROM `users`$").WillReturnRows(showColumns())
+	mockMariaDBDefaults(mock, "users")
+	assert.Equal(t, map[string]bool{
+		"id": false, "score": false, "created_at": true, "updated_at": true, "seen_at": true, "uid": false, "state": false,
+	}, expressions())
+
+	// MariaDB quotes the literals
+	mock.ExpectQuery("^SHOW COLUMNS FROM `users`$").WillReturnRows(showColumns())
+	mockMariaDBDefaults(mock, "users", "id", nil, "score", "0", "created_at", "current_timestamp()", "updated_at", "current_timestamp()",
+		"seen_at", "current_timestamp()", "uid", "uuid()", "state", "'CURRENT_TIMESTAMP'")
+	assert.Equal(t, map[string]bool{
+		"id": false, "score": false, "created_at": true, "updated_at": true, "seen_at": true, "uid": true, "state": false,
+	}, expressions())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDefaultLiteral(t *testing.T) {
+	cases := []struct {
+		expr   string
+		value  string
+		isNull bool
+		ok     bool
+	}{
+		{"'new'::character varying", "new", false, true},
+		{"'it''s'::text", "it's", false, true},
+		{"(-1)", "-1", false, true},
+		{"0.5::numeric", "0.5", false, true},
+		{"true", "true", false, true},
+		{"NULL::text", "", true, true},
+		{"now()", "", false, false},
+		{"nextval('users_id_seq'::regclass)", "", false, false},
+		{"'a'::text || 'b'::text", "", false, false},
+	}
+	for _, tc := range cases {
+		value, isNull, ok := defaultLiteral(tc.expr)
+		assert.Equal(t, tc.value, value, tc.expr)
+		assert.Equal(t, tc.isNull, isNull, tc.expr)
+		assert.Equal(t, tc.ok, ok, tc.expr)
+	}
+}
+
+func TestMysqlUniqueColumns(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer data.Close()
+
+	mock.ExpectQuery("^SHOW INDEX FROM `test`$").WillReturnRows(
+		sqlmock.NewRows([]string{"Table", "Non_unique", "Key_name", "Seq_in_index", "Column_name"}).
+			AddRow("test", 0, "PRIMARY", 1, "id").
+			AddRow("test", 0, "tenant_login", 1, "tenant_id").
+			AddRow("test", 0, "tenant_login", 2, "login").
+			AddRow("test", 1, "name", 1, "name").
+			AddRow("test", 0, "functional", 1, nil))
+
+	unique, err := data.dialect().UniqueColumns(data.tx, "test")
+	assert.NoError(t, err)
+	// login is unique per tenant only
+	assert.Equal(t, map[string]bool{"id": true}, unique)
+}
+
 func TestCreateTableOk(t *testing.T) {
 	data, mock, err := getMockData()
 	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
@@ -344,6 +1114,7 @@ func TestCreateTableOk(t *testing.T) {
 
 	mock.ExpectQuery("^SHOW CREATE TABLE `Test_Table`$").WillReturnRows(createTableRows)
 	mock.ExpectQuery("^SHOW COLUMNS FROM `Test_Table`$").WillReturnRows(createTableValueCols)
+	mockMaxLengths(mock, "Test_Table")
 	mock.ExpectQuery("^SELECT (.+) FROM `Test_Table`$").WillReturnRows(createTableValueRows)
 
 	var buf bytes.Buffer
@@ -413,6 +1184,7 @@ func TestCreateTableOkSmallPackets(t *testing.T) {
 
 	mock.ExpectQuery("^SHOW CREATE TABLE `Test_Table`$").WillReturnRows(createTableRows)
 	mock.ExpectQuery("^SHOW COLUMNS FROM `Test_Table`$").WillReturnRows(createTableValueCols)
+	mockMaxLengths(mock, "Test_Table")
 	mock.ExpectQuery("^SELECT (.+) FROM `Test_Table`$").WillReturnRows(createTableValueRows)
 
 	var buf bytes.Buffer
@@ -453,3 +1225,37 @@ UNLOCK TABLES;
 	result := strings.Replace(buf.String(), "`", "~", -1)
 	assert.Equal(t, expectedResult, result)
 }
+
+func TestMaxAllowedPacketFromServer(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer data.Close()
+
+	rows := sqlmock.NewRows([]string{"@@max_allowed_packet"}).
+		AddRow(67108864)
+
+	mock.ExpectQuery(`^SELECT @@max_allowed_packet$`).WillReturnRows(rows)
+
+	assert.NoError(t, data.updateMaxAllowedPacket())
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	// the batches are capped as the target server limit may be lower
+	assert.Equal(t, 1046528, data.MaxAllowedPacket)
+}
+
+func TestMaxAllowedPacketFromConfig(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer data.Close()
+
+	data.MaxAllowedPacket = 1024
+
+	assert.NoError(t, data.updateMaxAllowedPacket())
+
+	// no query is expected as the value is set explicitly
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.Equal(t, 1024, data.MaxAllowedPacket)
+}
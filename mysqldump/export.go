@@ -0,0 +1,210 @@
+package mysqldump
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/vicdeo/go-obfuscate/config"
+	"github.com/vicdeo/go-obfuscate/faker"
+)
+
+// rowWriter writes table rows into a per-table file
+type rowWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []interface{}) error
+	Flush() error
+}
+
+// csvRowWriter writes RFC 4180 CSV with a header line, NULL is written as the null marker
+type csvRowWriter struct {
+	w    *csv.Writer
+	null string
+}
+
+// ndjsonRowWriter writes a JSON object per line, keys follow the column order
+type ndjsonRowWriter struct {
+	w       *bufio.Writer
+	columns [][]byte
+}
+
+// tableSchema is written next to the data file to describe its columns
+type tableSchema struct {
+	Table   string         `json:"table"`
+	Format  string         `json:"format"`
+	File    string         `json:"file"`
+	Columns []columnSchema `json:"columns"`
+}
+
+type columnSchema struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable"`
+	Binary     bool   `json:"binary,omitempty"`
+	Obfuscated bool   `json:"obfuscated,omitempty"`
+}
+
+// exportTable writes the table data and its schema sidecar into the dump directory
+func (data *Data) exportTable(table *table) (err error) {
+	if err := table.Init(); err != nil {
+		return err
+	}
+	if len(table.cols) == 0 {
+		// No data to dump since this is a virtual table
+		return nil
+	}
+
+	fileName := table.Name + "." + data.Format
+	f, err := os.Create(filepath.Join(data.Directory, fileName))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	w, err := newRowWriter(data.Format, data.NullString, f)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteHeader(table.cols); err != nil {
+		return err
+	}
+	for table.Next() {
+		if err := w.WriteRow(table.row); err != nil {
+			return err
+		}
+	}
+	if table.Err != nil {
+		return table.Err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return data.writeSchema(table, fileName)
+}
+
+func (data *Data) writeSchema(table *table, fileName string) error {
+	schema := tableSchema{
+		Table:   table.Name,
+		Format:  data.Format,
+		File:    fileName,
+		Columns: make([]columnSchema, len(table.colTypes)),
+	}
+	for i, tp := range table.colTypes {
+		nullable, _ := tp.Nullable()
+		schema.Columns[i] = columnSchema{
+			Name:       table.cols[i],
+			Type:       tp.DatabaseTypeName(),
+			Nullable:   nullable,
+			Binary:     isRawBytes(table.values[i]),
+			Obfuscated: table.colFakers[i] != nil,
+		}
+	}
+
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(data.Directory, table.Name+".schema.json"), append(b, '\n'), 0666)
+}
+
+func isRawBytes(value interface{}) bool {
+	_, ok := value.(*sql.RawBytes)
+	return ok
+}
+
+func newRowWriter(format, null string, out io.Writer) (rowWriter, error) {
+	switch format {
+	case config.FormatCSV:
+		return &csvRowWriter{w: csv.NewWriter(out), null: null}, nil
+	case config.FormatNDJSON:
+		return &ndjsonRowWriter{w: bufio.NewWriter(out)}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
+
+func (cw *csvRowWriter) WriteHeader(columns []string) error {
+	return cw.w.Write(columns)
+}
+
+func (cw *csvRowWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch s := value.(type) {
+		case nil:
+			record[i] = cw.null
+		case string:
+			record[i] = s
+		case int64:
+			record[i] = strconv.FormatInt(s, 10)
+		case float64:
+			record[i] = strconv.FormatFloat(s, 'f', -1, 64)
+		case bool:
+			record[i] = strconv.FormatBool(s)
+		case []byte:
+			record[i] = base64.StdEncoding.EncodeToString(s)
+		default:
+			record[i] = fmt.Sprintf("%s", value)
+		}
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvRowWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (jw *ndjsonRowWriter) WriteHeader(columns []string) error {
+	jw.columns = make([][]byte, len(columns))
+	for i, column := range columns {
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		jw.columns[i] = key
+	}
+	return nil
+}
+
+func (jw *ndjsonRowWriter) WriteRow(values []interface{}) error {
+	jw.w.WriteByte('{')
+	for i, value := range values {
+		if i != 0 {
+			jw.w.WriteByte(',')
+		}
+		switch s := value.(type) {
+		case nil, string, int64, float64, bool, []byte:
+			// []byte is encoded as base64 string
+		case faker.Decimal:
+			value = json.Number(s)
+		default:
+			value = fmt.Sprintf("%s", value)
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		jw.w.Write(jw.columns[i])
+		jw.w.WriteByte(':')
+		jw.w.Write(b)
+	}
+	_, err := jw.w.WriteString("}\n")
+	return err
+}
+
+func (jw *ndjsonRowWriter) Flush() error {
+	return jw.w.Flush()
+}
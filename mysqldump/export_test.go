@@ -0,0 +1,103 @@
+package mysqldump
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vicdeo/go-obfuscate/config"
+	"github.com/vicdeo/go-obfuscate/faker"
+)
+
+func exportTestTable(t *testing.T, format string) string {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+
+	dir, err := ioutil.TempDir("", "export")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	data.Format = format
+	data.Directory = dir
+	data.NullString = config.DefaultNullString
+
+	cols := sqlmock.NewRows([]string{"Field", "Extra"}).
+		AddRow("id", "").
+		AddRow("email", "").
+		AddRow("name", "")
+
+	rows := sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("email", ""), c("name", "")).
+		AddRow(1, nil, "Test \"Name\" 1").
+		AddRow(2, "test2@test.de", "Test Name 2")
+
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(rows)
+
+	assert.NoError(t, data.exportTable(data.createTable("test")))
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	schema, err := ioutil.ReadFile(filepath.Join(dir, "test.schema.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(schema), `"file": "test.`+format+`"`)
+	assert.Contains(t, string(schema), `"name": "email"`)
+
+	result, err := ioutil.ReadFile(filepath.Join(dir, "test."+format))
+	assert.NoError(t, err)
+	return string(result)
+}
+
+func TestExportTableCSV(t *testing.T) {
+	expected := "id,email,name\n" +
+		"1,\\N,\"Test \"\"Name\"\" 1\"\n" +
+		"2,test2@test.de,Test Name 2\n"
+	assert.Equal(t, expected, exportTestTable(t, config.FormatCSV))
+}
+
+func TestExportTableNDJSON(t *testing.T) {
+	expected := `{"id":1,"email":null,"name":"Test \"Name\" 1"}` + "\n" +
+		`{"id":2,"email":"test2@test.de","name":"Test Name 2"}` + "\n"
+	assert.Equal(t, expected, exportTestTable(t, config.FormatNDJSON))
+}
+
+func TestExportTableDefaultExpression(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+		getColumnFaker = config.GetColumnFaker
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	getColumnFaker = func(tableName, columnName string) faker.FakeGenerator {
+		if columnName == "created_at" {
+			return &faker.FakeDefault{}
+		}
+		return nil
+	}
+	data.Format = config.FormatCSV
+
+	cols := sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+		AddRow("id", "int", "NO", "PRI", nil, "").
+		AddRow("created_at", "datetime", "NO", "", "CURRENT_TIMESTAMP", "DEFAULT_GENERATED")
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMaxLengths(mock, "test")
+
+	// The DEFAULT keyword has no counterpart in CSV
+	err = data.exportTable(data.createTable("test"))
+	assert.EqualError(t, err, "test.created_at: the default CURRENT_TIMESTAMP is an expression that is written in SQL dumps only, use another type")
+}
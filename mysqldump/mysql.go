@@ -0,0 +1,312 @@
+package mysqldump
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// mysqlDialect produces dumps restorable with the mysql client
+type mysqlDialect struct{}
+
+// Default expressions that MySQL before 8.0 shows without the DEFAULT_GENERATED marker, CURRENT_TIMESTAMP and its synonyms
+// are the only ones it allows and only for the temporal columns. MariaDB shows current_timestamp() for them
+var mysqlTimeDefaultRe = regexp.MustCompile(`(?i)^(current_timestamp|now|localtime|localtimestamp|current_date|current_time|curdate|curtime|utc_timestamp)(\([0-9]*\))?$`)
+
+// mariaDBDefaultsQuery reads the defaults from information_schema, MariaDB 10.2.7+ quotes the literals there unlike MySQL,
+// so an unquoted default is an expression. MySQL marks its expressions with DEFAULT_GENERATED instead and gets no rows
+const mariaDBDefaultsQuery = "SELECT COLUMN_NAME, COLUMN_DEFAULT FROM information_schema.COLUMNS " +
+	"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND VERSION() LIKE '%MariaDB%'"
+
+// takes a *metaData
+const mysqlHeaderTmpl = `-- Go SQL Dump {{ .DumpVersion }}
+--
+-- ------------------------------------------------------
+-- Server version	{{ .ServerVersion }}
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
+/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
+ SET NAMES utf8mb4 ;
+/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
+/*!40103 SET TIME_ZONE='+00:00' */;
+/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
+/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
+/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
+/*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
+`
+
+// takes a *metaData
+const mysqlFooterTmpl = `/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
+
+/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
+/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
+/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
+/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
+/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
+/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
+
+-- Dump completed on {{ .CompleteTime }}
+`
+
+// Takes a *table
+const mysqlTableTmpl = `
+--
+-- Table structure for table {{ .NameEsc }}
+--
+
+DROP TABLE IF EXISTS {{ .NameEsc }};
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+ SET character_set_client = utf8mb4 ;
+{{ .CreateSQL }};
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table {{ .NameEsc }}
+--
+
+LOCK TABLES {{ .NameEsc }} WRITE;
+/*!40000 ALTER TABLE {{ .NameEsc }} DISABLE KEYS */;
+{{ range $value := .Stream }}
+{{- $value }}
+{{ end -}}
+/*!40000 ALTER TABLE {{ .NameEsc }} ENABLE KEYS */;
+UNLOCK TABLES;
+`
+
+func (d *mysqlDialect) QuoteIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+func (d *mysqlDialect) QuoteString(value string) string {
+	return "'" + sanitize(value) + "'"
+}
+
+func (d *mysqlDialect) QuoteBinary(value []byte) string {
+	return "_binary '" + sanitize(string(value)) + "'"
+}
+
+func (d *mysqlDialect) Templates() (header, table, footer string) {
+	return mysqlHeaderTmpl, mysqlTableTmpl, mysqlFooterTmpl
+}
+
+func (d *mysqlDialect) TablesQuery() string {
+	return "SHOW TABLES"
+}
+
+func (d *mysqlDialect) ServerVersionQuery() string {
+	return "SELECT version()"
+}
+
+func (d *mysqlDialect) MaxAllowedPacketQuery() string {
+	return "SELECT @@max_allowed_packet"
+}
+
+func (d *mysqlDialect) MaxLengthQuery() string {
+	return "SELECT COLUMN_NAME, CHARACTER_MAXIMUM_LENGTH FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?"
+}
+
+func (d *mysqlDialect) LockTablesQuery(tables []string) string {
+	var b strings.Builder
+	b.WriteString("LOCK TABLES ")
+	for index, name := range tables {
+		if index != 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(d.QuoteIdentifier(name) + " READ /*!32311 LOCAL */")
+	}
+	return b.String()
+}
+
+func (d *mysqlDialect) TableDefinition(tx *sql.Tx, name string) (*tableDefinition, error) {
+	var tableReturn, tableSQL sql.NullString
+	if err := tx.QueryRow("SHOW CREATE TABLE "+d.QuoteIdentifier(name)).Scan(&tableReturn, &tableSQL); err != nil {
+		return nil, err
+	}
+
+	if tableReturn.String != name {
+		return nil, errors.New("Returned table is not the same as requested table")
+	}
+
+	return &tableDefinition{Create: tableSQL.String}, nil
+}
+
+func (d *mysqlDialect) Columns(tx *sql.Tx, name string) ([]column, error) {
+	colInfo, err := tx.Query("SHOW COLUMNS FROM " + d.QuoteIdentifier(name))
+	if err != nil {
+		return nil, err
+	}
+	defer colInfo.Close()
+
+	cols, err := colInfo.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	fieldIndex, typeIndex, nullIndex, keyIndex, defaultIndex, extraIndex := -1, -1, -1, -1, -1, -1
+	for i, col := range cols {
+		switch strings.ToLower(col) {
+		case "field":
+			fieldIndex = i
+		case "type":
+			typeIndex = i
+		case "null":
+			nullIndex = i
+		case "key":
+			keyIndex = i
+		case "default":
+			defaultIndex = i
+		case "extra":
+			extraIndex = i
+		}
+	}
+	if fieldIndex < 0 || extraIndex < 0 {
+		return nil, errors.New("database column information is malformed")
+	}
+
+	info := make([]sql.NullString, len(cols))
+	scans := make([]interface{}, len(cols))
+	for i := range info {
+		scans[i] = &info[i]
+	}
+
+	var result []column
+	for colInfo.Next() {
+		// Read into the pointers to the info marker
+		if err := colInfo.Scan(scans...); err != nil {
+			return nil, err
+		}
+
+		col := column{
+			Name:     info[fieldIndex].String,
+			Extra:    info[extraIndex].String,
+			Nullable: true,
+		}
+		if typeIndex >= 0 {
+			col.Type = info[typeIndex].String
+		}
+		if nullIndex >= 0 && info[nullIndex].Valid {
+			col.Nullable = strings.EqualFold(info[nullIndex].String, "YES")
+		}
+		if keyIndex >= 0 {
+			col.Key = info[keyIndex].String
+		}
+		if defaultIndex >= 0 {
+			col.Default = info[defaultIndex]
+		}
+		// Virtual columns can't be inserted
+		col.Generated = strings.Contains(col.Extra, "VIRTUAL")
+		col.DefaultExpr = mysqlDefaultExpr(col)
+		result = append(result, col)
+	}
+	if err := colInfo.Err(); err != nil {
+		return nil, err
+	}
+	return result, mariaDBDefaultExprs(tx, name, result)
+}
+
+// mysqlDefaultExpr tells whether the default is an expression, MySQL 8.0 marks them and the older versions allow
+// CURRENT_TIMESTAMP of the temporal columns only
+func mysqlDefaultExpr(col column) bool {
+	if strings.Contains(col.Extra, "DEFAULT_GENERATED") {
+		return true
+	}
+	if !col.Default.Valid {
+		return false
+	}
+	columnType := strings.ToLower(col.Type)
+	temporal := strings.HasPrefix(columnType, "date") || strings.HasPrefix(columnType, "time") || strings.HasPrefix(columnType, "year")
+	return temporal && mysqlTimeDefaultRe.MatchString(strings.TrimSpace(col.Default.String))
+}
+
+// mariaDBDefaultExprs marks the expression defaults of MariaDB, SHOW COLUMNS shows them like the literals
+func mariaDBDefaultExprs(tx *sql.Tx, name string, cols []column) error {
+	unknown := make(map[string]*column)
+	for i, col := range cols {
+		// A number is the same value whether it is a literal or an expression
+		if _, err := strconv.ParseFloat(col.Default.String, 64); col.Default.Valid && !col.DefaultExpr && err != nil {
+			unknown[col.Name] = &cols[i]
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query(mariaDBDefaultsQuery, name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var columnName string
+		var columnDefault sql.NullString
+		if err := rows.Scan(&columnName, &columnDefault); err != nil {
+			return err
+		}
+		if col, ok := unknown[columnName]; ok && columnDefault.Valid && !strings.HasPrefix(columnDefault.String, "'") {
+			col.DefaultExpr = true
+		}
+	}
+	return rows.Err()
+}
+
+func (d *mysqlDialect) UniqueColumns(tx *sql.Tx, name string) (map[string]bool, error) {
+	indexInfo, err := tx.Query("SHOW INDEX FROM " + d.QuoteIdentifier(name))
+	if err != nil {
+		return nil, err
+	}
+	defer indexInfo.Close()
+
+	cols, err := indexInfo.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	nonUniqueIndex, keyIndex, columnIndex := -1, -1, -1
+	for i, col := range cols {
+		switch strings.ToLower(col) {
+		case "non_unique":
+			nonUniqueIndex = i
+		case "key_name":
+			keyIndex = i
+		case "column_name":
+			columnIndex = i
+		}
+	}
+	if nonUniqueIndex < 0 || keyIndex < 0 || columnIndex < 0 {
+		return nil, errors.New("database index information is malformed")
+	}
+
+	info := make([]sql.NullString, len(cols))
+	scans := make([]interface{}, len(cols))
+	for i := range info {
+		scans[i] = &info[i]
+	}
+
+	// keyColumns lists the columns of the unique keys, a key has a row per part
+	keyColumns := make(map[string][]sql.NullString)
+	for indexInfo.Next() {
+		if err := indexInfo.Scan(scans...); err != nil {
+			return nil, err
+		}
+		if info[nonUniqueIndex].String == "0" {
+			key := info[keyIndex].String
+			keyColumns[key] = append(keyColumns[key], info[columnIndex])
+		}
+	}
+	if err := indexInfo.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool)
+	for _, columns := range keyColumns {
+		// Composite keys don't make their columns unique, functional key parts have no column name
+		if len(columns) == 1 && columns[0].Valid {
+			result[columns[0].String] = true
+		}
+	}
+	return result, nil
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+
 	"github.com/vicdeo/go-obfuscate/config"
+	"github.com/vicdeo/go-obfuscate/faker"
 )
 
 /*
@@ -15,16 +18,40 @@ Register a new dumper.
 	conf: config read from the file
 */
 func Register(db *sql.DB, conf *config.Config) (*Data, error) {
+	dialect, err := NewDialect(conf.Database.GetDriver())
+	if err != nil {
+		return nil, err
+	}
+
+	data := &Data{
+		Format:           conf.Output.GetFormat(),
+		Connection:       db,
+		Dialect:          dialect,
+		MaxAllowedPacket: conf.Output.MaxAllowedPacket,
+		NullString:       conf.Output.GetNullString(),
+	}
+
+	if data.Format == config.FormatSQLite {
+		data.File = conf.GetDumpFullPath()
+		return data, nil
+	}
+
+	if !data.writesSQL() {
+		// Create a directory for per-table files
+		if err := os.MkdirAll(conf.GetDumpFullPath(), 0777); err != nil {
+			return nil, err
+		}
+		data.Directory = conf.GetDumpFullPath()
+		return data, nil
+	}
+
 	// Create .sql file
 	f, err := os.Create(conf.GetDumpFullPath())
 	if err != nil {
 		return nil, err
 	}
-
-	return &Data{
-		Out:        f,
-		Connection: db,
-	}, nil
+	data.Out = f
+	return data, nil
 }
 
 // Dump Creates a MYSQL dump from the connection to the stream.
@@ -51,8 +78,8 @@ func (d *Data) Close() error {
 	return d.Connection.Close()
 }
 
-func ShowTables(db *sql.DB) ([]string, error) {
-	rows0, err := db.Query("show tables")
+func ShowTables(db *sql.DB, dialect Dialect) ([]string, error) {
+	rows0, err := db.Query(dialect.TablesQuery())
 	if err != nil {
 		return nil, err
 	}
@@ -74,3 +101,46 @@ func ShowTables(db *sql.DB) ([]string, error) {
 
 	return tables, nil
 }
+
+// CheckColumns tells which obfuscated columns are missing in the tables or can't get the configured values,
+// each message holds table name, column name and the reason
+func CheckColumns(db *sql.DB, dialect Dialect, columns map[string][]string) ([][]string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tables := make([]string, 0, len(columns))
+	for name := range columns {
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+
+	messages := make([][]string, 0)
+	for _, name := range tables {
+		dbColumns, err := dialect.Columns(tx, name)
+		if err != nil {
+			return nil, err
+		}
+		byName := make(map[string]column, len(dbColumns))
+		for _, col := range dbColumns {
+			byName[col.Name] = col
+		}
+		for _, c := range columns[name] {
+			col, ok := byName[c]
+			if !ok {
+				messages = append(messages, []string{name, c, "column is not found"})
+				continue
+			}
+			generator := getColumnFaker(name, c)
+			if generator == nil {
+				continue
+			}
+			if err := faker.CheckColumn(generator, fakerColumn(col)); err != nil {
+				messages = append(messages, []string{name, c, err.Error()})
+			}
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,276 @@
+package mysqldump
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// postgresDialect produces dumps restorable with psql
+type postgresDialect struct{}
+
+// takes a *metaData
+const postgresHeaderTmpl = `-- Go SQL Dump {{ .DumpVersion }}
+--
+-- ------------------------------------------------------
+-- Server version	{{ .ServerVersion }}
+
+SET statement_timeout = 0;
+SET lock_timeout = 0;
+SET client_encoding = 'UTF8';
+SET standard_conforming_strings = on;
+SET check_function_bodies = false;
+SET client_min_messages = warning;
+`
+
+// takes a *metaData
+const postgresFooterTmpl = `{{ if .DeferredSQL }}
+--
+-- Foreign keys
+--
+
+{{ range .DeferredSQL }}{{ . }};
+{{ end }}{{ end }}
+-- Dump completed on {{ .CompleteTime }}
+`
+
+// Takes a *table
+const postgresTableTmpl = `
+--
+-- Table structure for table {{ .NameEsc }}
+--
+
+DROP TABLE IF EXISTS {{ .NameEsc }} CASCADE;
+{{ .CreateSQL }};
+
+--
+-- Dumping data for table {{ .NameEsc }}
+--
+
+{{ range $value := .Stream }}
+{{- $value }}
+{{ end -}}
+{{ with .PostDataSQL }}{{ . }}
+{{ end -}}
+`
+
+const postgresColumnsQuery = `SELECT a.attname,
+	pg_catalog.format_type(a.atttypid, a.atttypmod),
+	NOT a.attnotnull,
+	CASE
+		WHEN EXISTS (SELECT 1 FROM pg_catalog.pg_index i WHERE i.indrelid = a.attrelid AND i.indisprimary AND a.attnum = ANY(i.indkey)) THEN 'PRI'
+		WHEN EXISTS (SELECT 1 FROM pg_catalog.pg_index i WHERE i.indrelid = a.attrelid AND i.indisunique AND i.indnatts = 1 AND i.indkey[0] = a.attnum) THEN 'UNI'
+		ELSE ''
+	END,
+	pg_catalog.pg_get_expr(d.adbin, d.adrelid),
+	a.attidentity,
+	a.attgenerated
+FROM pg_catalog.pg_attribute a
+LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`
+
+const postgresConstraintsQuery = `SELECT conname, contype, pg_catalog.pg_get_constraintdef(oid)
+FROM pg_catalog.pg_constraint
+WHERE conrelid = $1::regclass
+ORDER BY contype, conname`
+
+// Indexes that are not backing a constraint
+const postgresIndexesQuery = `SELECT pg_catalog.pg_get_indexdef(i.indexrelid)
+FROM pg_catalog.pg_index i
+WHERE i.indrelid = $1::regclass AND NOT EXISTS (
+	SELECT 1 FROM pg_catalog.pg_constraint c WHERE c.conrelid = i.indrelid AND c.conindid = i.indexrelid
+)
+ORDER BY i.indexrelid`
+
+const postgresUniqueColumnsQuery = `SELECT DISTINCT a.attname
+FROM pg_catalog.pg_index i
+JOIN pg_catalog.pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+WHERE i.indrelid = $1::regclass AND i.indisunique AND i.indnatts = 1`
+
+const (
+	postgresIdentityAlways    = "a"
+	postgresIdentityByDefault = "d"
+	postgresGeneratedStored   = "s"
+	postgresForeignKey        = "f"
+)
+
+var (
+	postgresSequenceRe = regexp.MustCompile(`^nextval\('([^']+)'::regclass\)$`)
+
+	// E'' strings keep each value on a single line
+	postgresReplacer = strings.NewReplacer(
+		"\\", "\\\\",
+		"'", "\\'",
+		"\n", "\\n",
+		"\r", "\\r",
+	)
+)
+
+func (d *postgresDialect) QuoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+func (d *postgresDialect) QuoteString(value string) string {
+	return "E'" + postgresReplacer.Replace(value) + "'"
+}
+
+func (d *postgresDialect) QuoteBinary(value []byte) string {
+	return `'\x` + hex.EncodeToString(value) + `'::bytea`
+}
+
+func (d *postgresDialect) Templates() (header, table, footer string) {
+	return postgresHeaderTmpl, postgresTableTmpl, postgresFooterTmpl
+}
+
+func (d *postgresDialect) TablesQuery() string {
+	return "SELECT tablename FROM pg_catalog.pg_tables WHERE schemaname = current_schema() ORDER BY tablename"
+}
+
+func (d *postgresDialect) ServerVersionQuery() string {
+	return "SHOW server_version"
+}
+
+func (d *postgresDialect) MaxAllowedPacketQuery() string {
+	return ""
+}
+
+func (d *postgresDialect) MaxLengthQuery() string {
+	return "SELECT column_name, character_maximum_length FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1"
+}
+
+// LockTablesQuery is not needed as the repeatable read transaction gives a consistent snapshot
+func (d *postgresDialect) LockTablesQuery(tables []string) string {
+	return ""
+}
+
+func (d *postgresDialect) TableDefinition(tx *sql.Tx, name string) (*tableDefinition, error) {
+	cols, err := d.Columns(tx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("table %s has no columns or does not exist", name)
+	}
+
+	def := &tableDefinition{}
+	var pre, post, lines []string
+	tableName := d.QuoteIdentifier(name)
+	for _, col := range cols {
+		colName := d.QuoteIdentifier(col.Name)
+		line := "  " + colName + " " + col.Type
+		switch {
+		case col.Generated:
+			line += " GENERATED ALWAYS AS (" + col.Default.String + ") STORED"
+		case col.Extra != "":
+			// Identity columns are restored with explicit values, so they can't be GENERATED ALWAYS
+			line += " GENERATED BY DEFAULT AS IDENTITY"
+			post = append(post, "SELECT pg_catalog.setval(pg_catalog.pg_get_serial_sequence("+
+				d.QuoteString(tableName)+", "+d.QuoteString(col.Name)+"), COALESCE(MAX("+colName+"), 0) + 1, false) FROM "+tableName+";")
+		case col.Default.Valid:
+			if m := postgresSequenceRe.FindStringSubmatch(col.Default.String); m != nil {
+				pre = append(pre, "CREATE SEQUENCE IF NOT EXISTS "+m[1]+";")
+				post = append(post,
+					"ALTER SEQUENCE "+m[1]+" OWNED BY "+tableName+"."+colName+";",
+					"SELECT pg_catalog.setval('"+m[1]+"', COALESCE(MAX("+colName+"), 0) + 1, false) FROM "+tableName+";",
+				)
+			}
+			line += " DEFAULT " + col.Default.String
+		}
+		if !col.Nullable {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+
+	constraints, err := tx.Query(postgresConstraintsQuery, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer constraints.Close()
+	for constraints.Next() {
+		var conName, conType, conDef string
+		if err := constraints.Scan(&conName, &conType, &conDef); err != nil {
+			return nil, err
+		}
+		constraint := "CONSTRAINT " + d.QuoteIdentifier(conName) + " " + conDef
+		if conType == postgresForeignKey {
+			// Referenced table could be restored later
+			def.Deferred = append(def.Deferred, "ALTER TABLE "+tableName+" ADD "+constraint)
+		} else {
+			lines = append(lines, "  "+constraint)
+		}
+	}
+	if err := constraints.Err(); err != nil {
+		return nil, err
+	}
+
+	indexes, err := tx.Query(postgresIndexesQuery, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer indexes.Close()
+	var indexDefs []string
+	for indexes.Next() {
+		var indexDef string
+		if err := indexes.Scan(&indexDef); err != nil {
+			return nil, err
+		}
+		indexDefs = append(indexDefs, indexDef+";")
+	}
+	if err := indexes.Err(); err != nil {
+		return nil, err
+	}
+	post = append(indexDefs, post...)
+
+	def.Create = strings.Join(append(pre, "CREATE TABLE "+tableName+" (\n"+strings.Join(lines, ",\n")+"\n)"), "\n")
+	def.PostData = strings.Join(post, "\n")
+	return def, nil
+}
+
+func (d *postgresDialect) Columns(tx *sql.Tx, name string) ([]column, error) {
+	colInfo, err := tx.Query(postgresColumnsQuery, d.QuoteIdentifier(name))
+	if err != nil {
+		return nil, err
+	}
+	defer colInfo.Close()
+
+	var result []column
+	for colInfo.Next() {
+		var col column
+		var identity, generated sql.NullString
+		if err := colInfo.Scan(&col.Name, &col.Type, &col.Nullable, &col.Key, &col.Default, &identity, &generated); err != nil {
+			return nil, err
+		}
+		col.DefaultExpr = col.Default.Valid
+		switch identity.String {
+		case postgresIdentityAlways:
+			col.Extra = "identity always"
+		case postgresIdentityByDefault:
+			col.Extra = "identity by default"
+		}
+		col.Generated = generated.String == postgresGeneratedStored
+		result = append(result, col)
+	}
+	return result, colInfo.Err()
+}
+
+func (d *postgresDialect) UniqueColumns(tx *sql.Tx, name string) (map[string]bool, error) {
+	rows, err := tx.Query(postgresUniqueColumnsQuery, d.QuoteIdentifier(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]bool)
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, err
+		}
+		result[col] = true
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,139 @@
+package mysqldump
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vicdeo/go-obfuscate/config"
+)
+
+func TestPostgresQuoteString(t *testing.T) {
+	d := &postgresDialect{}
+	assert.Equal(t, `E'it\'s a \\ test\nline'`, d.QuoteString("it's a \\ test\nline"))
+	assert.Equal(t, `'\x00ff'::bytea`, d.QuoteBinary([]byte{0, 255}))
+	assert.Equal(t, `"weird""name"`, d.QuoteIdentifier(`weird"name`))
+}
+
+func mockPostgresColumns(mock sqlmock.Sqlmock, name string) {
+	cols := sqlmock.NewRows([]string{"attname", "format_type", "nullable", "key", "default", "attidentity", "attgenerated"}).
+		AddRow("id", "integer", false, "PRI", "nextval('users_id_seq'::regclass)", "", "").
+		AddRow("email", "character varying(255)", true, "UNI", nil, "", "").
+		AddRow("email_lower", "text", true, "", "lower((email)::text)", "", "s")
+
+	mock.ExpectQuery("FROM pg_catalog.pg_attribute").WithArgs(`"` + name + `"`).WillReturnRows(cols)
+}
+
+func TestPostgresTableDefinition(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer data.Close()
+	data.Dialect = &postgresDialect{}
+
+	mockPostgresColumns(mock, "users")
+	mock.ExpectQuery("FROM pg_catalog.pg_constraint").WithArgs(`"users"`).WillReturnRows(
+		sqlmock.NewRows([]string{"conname", "contype", "pg_get_constraintdef"}).
+			AddRow("users_org_fk", "f", "FOREIGN KEY (org_id) REFERENCES orgs(id)").
+			AddRow("users_pkey", "p", "PRIMARY KEY (id)"))
+	mock.ExpectQuery("FROM pg_catalog.pg_index").WithArgs(`"users"`).WillReturnRows(
+		sqlmock.NewRows([]string{"pg_get_indexdef"}).
+			AddRow("CREATE INDEX users_email_idx ON public.users USING btree (email)"))
+
+	def, err := data.createTable("users").definition()
+	assert.NoError(t, err)
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.Equal(t, `CREATE SEQUENCE IF NOT EXISTS users_id_seq;
+CREATE TABLE "users" (
+  "id" integer DEFAULT nextval('users_id_seq'::regclass) NOT NULL,
+  "email" character varying(255),
+  "email_lower" text GENERATED ALWAYS AS (lower((email)::text)) STORED,
+  CONSTRAINT "users_pkey" PRIMARY KEY (id)
+)`, def.Create)
+	assert.Equal(t, `CREATE INDEX users_email_idx ON public.users USING btree (email);
+ALTER SEQUENCE users_id_seq OWNED BY "users"."id";
+SELECT pg_catalog.setval('users_id_seq', COALESCE(MAX("id"), 0) + 1, false) FROM "users";`, def.PostData)
+	assert.Equal(t, []string{`ALTER TABLE "users" ADD CONSTRAINT "users_org_fk" FOREIGN KEY (org_id) REFERENCES orgs(id)`}, def.Deferred)
+}
+
+func TestPostgresTableValuesStream(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	data.Dialect = &postgresDialect{}
+	data.MaxAllowedPacket = 4096
+
+	mockPostgresColumns(mock, "users")
+	mockMaxLengths(mock, "users")
+	rows := sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("email", "")).
+		AddRow(1, "o'hara@test.de").
+		AddRow(2, nil)
+	mock.ExpectQuery(`^SELECT "id", "email" FROM "users"$`).WillReturnRows(rows)
+
+	var buf bytes.Buffer
+	for value := range data.createTable("users").Stream() {
+		buf.WriteString(value)
+	}
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.Equal(t, `INSERT INTO "users" ("id", "email") VALUES (1,E'o\'hara@test.de'),(2,NULL);`, buf.String())
+}
+
+func TestPostgresUnknownTypesAreText(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+	data.Dialect = &postgresDialect{}
+	data.MaxAllowedPacket = 4096
+
+	cols := sqlmock.NewRows([]string{"attname", "format_type", "nullable", "key", "default", "attidentity", "attgenerated"}).
+		AddRow("tags", "text[]", true, "", nil, "", "").
+		AddRow("ip", "inet", true, "", nil, "", "")
+	mock.ExpectQuery("FROM pg_catalog.pg_attribute").WithArgs(`"hosts"`).WillReturnRows(cols)
+	mockMaxLengths(mock, "hosts")
+	// lib/pq scans the types it doesn't know into interface{}
+	rows := sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("tags").OfType("_TEXT", struct{}{}),
+		sqlmock.NewColumn("ip").OfType("INET", struct{}{})).
+		AddRow("{a,b}", "10.0.0.1").
+		AddRow(nil, nil)
+	mock.ExpectQuery(`^SELECT "tags", "ip" FROM "hosts"$`).WillReturnRows(rows)
+
+	var buf bytes.Buffer
+	for value := range data.createTable("hosts").Stream() {
+		buf.WriteString(value)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+	assert.Equal(t, `INSERT INTO "hosts" ("tags", "ip") VALUES (E'{a,b}',E'10.0.0.1'),(NULL,NULL);`, buf.String())
+}
+
+func TestPostgresFooterDeferred(t *testing.T) {
+	data := &Data{Dialect: &postgresDialect{}}
+	assert.NoError(t, data.getTemplates())
+
+	var buf bytes.Buffer
+	assert.NoError(t, data.footerTmpl.Execute(&buf, metaData{
+		DeferredSQL:  []string{`ALTER TABLE "users" ADD CONSTRAINT "fk" FOREIGN KEY (org_id) REFERENCES orgs(id)`},
+		CompleteTime: "now",
+	}))
+	assert.True(t, strings.Contains(buf.String(), "REFERENCES orgs(id);\n\n-- Dump completed on now"))
+}
@@ -0,0 +1,130 @@
+package mysqldump
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// maxServerPacket is the largest max_allowed_packet a MySQL server accepts
+	maxServerPacket = 1073741824
+	// packetBlock is the multiple the server rounds max_allowed_packet down to
+	packetBlock = 1024
+)
+
+// RestoreError tells which statement of the dump failed
+type RestoreError struct {
+	// Line is the first line of the statement
+	Line int
+	Err  error
+}
+
+func (e *RestoreError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RestoreError) Unwrap() error {
+	return e.Err
+}
+
+// Restore executes an SQL dump written by Dump and returns the number of executed statements
+// Dumped values never span lines, so a statement ends with the line ending with a semicolon
+func Restore(db *sql.DB, r io.Reader) (int, error) {
+	ctx := context.Background()
+	// Session settings and table locks of the dump need a single connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	count := 0
+	err = readStatements(r, func(statement string, line int) error {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return &RestoreError{Line: line, Err: err}
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// LongestStatement returns the size in bytes of the largest statement of an SQL dump
+func LongestStatement(r io.Reader) (int, error) {
+	longest := 0
+	err := readStatements(r, func(statement string, line int) error {
+		if len(statement) > longest {
+			longest = len(statement)
+		}
+		return nil
+	})
+	return longest, err
+}
+
+// RaiseMaxAllowedPacket raises the global max_allowed_packet of a MySQL server so that a statement of the given size fits
+// and returns the previous global value, 0 if it is large enough. The session value is read-only and copied from the global
+// one on connect, so only the connections opened afterwards get the new limit
+func RaiseMaxAllowedPacket(db *sql.DB, size int) (int, error) {
+	var previous int
+	if err := db.QueryRow("SELECT @@GLOBAL.max_allowed_packet").Scan(&previous); err != nil {
+		return 0, err
+	}
+	// The packet carries a command byte besides the statement
+	size = (size + packetBlock) / packetBlock * packetBlock
+	if size > maxServerPacket {
+		size = maxServerPacket
+	}
+	if size <= previous {
+		return 0, nil
+	}
+	return previous, SetMaxAllowedPacket(db, size)
+}
+
+// SetMaxAllowedPacket sets the global max_allowed_packet of a MySQL server
+func SetMaxAllowedPacket(db *sql.DB, size int) error {
+	_, err := db.Exec(fmt.Sprintf("SET GLOBAL max_allowed_packet = %d", size))
+	return err
+}
+
+// readStatements calls fn with every statement of an SQL dump and the line it starts at
+func readStatements(r io.Reader, fn func(statement string, line int) error) error {
+	var statement strings.Builder
+	lineNum, startLine := 0, 0
+	reader := bufio.NewReader(r)
+	for {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		if line != "" {
+			lineNum++
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case statement.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")):
+			// Comments between statements
+		default:
+			if statement.Len() == 0 {
+				startLine = lineNum
+			}
+			statement.WriteString(line)
+			if strings.HasSuffix(trimmed, ";") {
+				if err := fn(statement.String(), startLine); err != nil {
+					return err
+				}
+				statement.Reset()
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+	if statement.Len() != 0 {
+		return &RestoreError{Line: startLine, Err: io.ErrUnexpectedEOF}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package mysqldump
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const restoreDump = `-- Go SQL Dump 0.9.0
+--
+
+/*!40101 SET NAMES utf8mb4 */;
+
+--
+-- Table structure for table ` + "`Test_Table`" + `
+--
+
+CREATE TABLE ` + "`Test_Table`" + ` (
+  ` + "`id`" + ` int(11) NOT NULL,
+  PRIMARY KEY (` + "`id`" + `)
+);
+INSERT INTO ` + "`Test_Table`" + ` VALUES (1),(2);
+-- Dump completed on 2019-01-01 00:00:00
+`
+
+func TestRestoreOk(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer db.Close()
+
+	mock.ExpectExec("^/\\*!40101 SET NAMES utf8mb4 \\*/;").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^CREATE TABLE (.+)PRIMARY KEY(.+)\\);").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^INSERT INTO (.+) VALUES \\(1\\),\\(2\\);").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	count, err := Restore(db, strings.NewReader(restoreDump))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreFailedStatement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer db.Close()
+
+	mock.ExpectExec("^/\\*!40101 SET NAMES utf8mb4 \\*/;").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^CREATE TABLE").WillReturnError(errors.New("syntax error"))
+
+	count, err := Restore(db, strings.NewReader(restoreDump))
+	assert.Equal(t, 1, count)
+	var restoreErr *RestoreError
+	if assert.True(t, errors.As(err, &restoreErr)) {
+		assert.Equal(t, 10, restoreErr.Line)
+		assert.EqualError(t, restoreErr.Err, "syntax error")
+	}
+}
+
+func TestRestoreTruncatedDump(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer db.Close()
+
+	mock.ExpectExec("^/\\*!40101 SET NAMES utf8mb4 \\*/;").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = Restore(db, strings.NewReader(restoreDump[:strings.Index(restoreDump, "PRIMARY")]))
+	var restoreErr *RestoreError
+	if assert.True(t, errors.As(err, &restoreErr)) {
+		assert.Equal(t, 10, restoreErr.Line)
+	}
+}
+
+func TestLongestStatement(t *testing.T) {
+	size, err := LongestStatement(strings.NewReader(restoreDump))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Index(restoreDump, "INSERT")-strings.Index(restoreDump, "CREATE"), size)
+}
+
+func TestRaiseMaxAllowedPacket(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer db.Close()
+
+	mock.ExpectQuery("^SELECT @@GLOBAL.max_allowed_packet$").
+		WillReturnRows(sqlmock.NewRows([]string{"@@GLOBAL.max_allowed_packet"}).AddRow(4194304))
+	mock.ExpectExec("^SET GLOBAL max_allowed_packet = 5243904$").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	previous, err := RaiseMaxAllowedPacket(db, 5242880)
+	assert.NoError(t, err)
+	assert.Equal(t, 4194304, previous)
+
+	mock.ExpectQuery("^SELECT @@GLOBAL.max_allowed_packet$").
+		WillReturnRows(sqlmock.NewRows([]string{"@@GLOBAL.max_allowed_packet"}).AddRow(4194304))
+
+	previous, err = RaiseMaxAllowedPacket(db, 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, previous, "the limit is large enough")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,235 @@
+package mysqldump
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	// Pure Go SQLite driver, no cgo required
+	_ "modernc.org/sqlite"
+)
+
+var (
+	sqliteTypeRe = regexp.MustCompile(`^([a-z ]+?)\s*(\(.*\))?(\s+(unsigned|signed|zerofill|with time zone|without time zone))*(\[\])?$`)
+
+	// Default expressions that have a SQLite counterpart
+	sqliteCastLiteralRe = regexp.MustCompile(`^('(?:[^']|'')*')::[a-z ]+$`)
+	sqliteNumberRe      = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	sqliteTimestampRe   = regexp.MustCompile(`(?i)^(now\(\)|current_timestamp(\([0-9]*\))?|localtimestamp(\([0-9]*\))?)$`)
+)
+
+// openSQLite creates the SQLite database file to write the tables into
+func (data *Data) openSQLite() (err error) {
+	data.sqlite, err = sql.Open("sqlite", data.File)
+	if err != nil {
+		return err
+	}
+	// The file is written once, durability is not a concern
+	for _, pragma := range []string{"PRAGMA journal_mode = OFF", "PRAGMA synchronous = OFF"} {
+		if _, err = data.sqlite.Exec(pragma); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (data *Data) closeSQLite() error {
+	return data.sqlite.Close()
+}
+
+// warn records a problem that does not stop the dump
+func (data *Data) warn(format string, a ...interface{}) {
+	data.Warnings = append(data.Warnings, fmt.Sprintf(format, a...))
+}
+
+// writeSQLiteTable recreates the table in the SQLite database and copies its rows
+func (data *Data) writeSQLiteTable(table *table) error {
+	// The definition is loaded before the rows are selected, the connection is busy until they are read
+	if _, err := table.definition(); err != nil {
+		return err
+	}
+	if err := table.Init(); err != nil {
+		return err
+	}
+	if len(table.cols) == 0 {
+		// No data to dump since this is a virtual table
+		return nil
+	}
+
+	createSQL, err := data.sqliteCreateSQL(table)
+	if err != nil {
+		return err
+	}
+
+	tx, err := data.sqlite.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DROP TABLE IF EXISTS " + sqliteQuote(table.Name)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(createSQL); err != nil {
+		return err
+	}
+
+	quoted := make([]string, len(table.cols))
+	for i, col := range table.cols {
+		quoted[i] = sqliteQuote(col)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(table.cols)), ",")
+	insert, err := tx.Prepare("INSERT INTO " + sqliteQuote(table.Name) + " (" + strings.Join(quoted, ", ") + ") VALUES (" + placeholders + ")")
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+
+	values := make([]interface{}, len(table.cols))
+	for table.Next() {
+		for i, value := range table.row {
+			switch value.(type) {
+			case nil, string, int64, float64, bool, []byte:
+				values[i] = value
+			default:
+				values[i] = fmt.Sprintf("%s", value)
+			}
+		}
+		if _, err := insert.Exec(values...); err != nil {
+			return err
+		}
+	}
+	if table.Err != nil {
+		return table.Err
+	}
+	return tx.Commit()
+}
+
+// sqliteCreateSQL translates the source table definition into SQLite DDL
+// Everything that can't be translated is reported as a warning
+func (data *Data) sqliteCreateSQL(table *table) (string, error) {
+	var lines, primaryKey []string
+	for _, col := range table.columns {
+		affinity, known := sqliteAffinity(col.Type)
+		if !known {
+			data.warn("%s.%s: type %s is stored as %s", table.Name, col.Name, col.Type, affinity)
+		}
+		if isEnumType(col.Type) {
+			data.warn("%s.%s: allowed values of %s are not enforced", table.Name, col.Name, col.Type)
+		}
+
+		line := "  " + sqliteQuote(col.Name) + " " + affinity
+		if !col.Nullable {
+			line += " NOT NULL"
+		}
+		if col.Key == "UNI" {
+			line += " UNIQUE"
+		}
+		if col.Key == "PRI" {
+			primaryKey = append(primaryKey, sqliteQuote(col.Name))
+		}
+		if defaultSQL, ok := sqliteDefault(col); ok {
+			if defaultSQL != "" {
+				line += " DEFAULT " + defaultSQL
+			}
+		} else {
+			data.warn("%s.%s: default %s is not translated", table.Name, col.Name, col.Default.String)
+		}
+		if strings.Contains(strings.ToLower(col.Extra), "on update") {
+			data.warn("%s.%s: %s is not translated", table.Name, col.Name, col.Extra)
+		}
+		lines = append(lines, line)
+	}
+	if len(primaryKey) > 0 {
+		lines = append(lines, "  PRIMARY KEY ("+strings.Join(primaryKey, ", ")+")")
+	}
+
+	for _, name := range table.generated {
+		data.warn("%s.%s: generated column is omitted", table.Name, name)
+	}
+
+	def, err := table.definition()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range append(strings.Split(def.Create, "\n"), def.Deferred...) {
+		line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), ","))
+		upper := strings.ToUpper(line)
+		switch {
+		case strings.Contains(upper, "FOREIGN KEY"),
+			strings.HasPrefix(upper, "CHECK"),
+			strings.Contains(upper, " CHECK ("),
+			// Only single column unique keys are translated
+			strings.Contains(upper, "UNIQUE") && strings.Contains(line, ","):
+			data.warn("%s: constraint is not translated: %s", table.Name, line)
+		}
+	}
+
+	return "CREATE TABLE " + sqliteQuote(table.Name) + " (\n" + strings.Join(lines, ",\n") + "\n)", nil
+}
+
+// sqliteAffinity maps a source column type to a SQLite type affinity
+func sqliteAffinity(columnType string) (string, bool) {
+	m := sqliteTypeRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(columnType)))
+	if m == nil {
+		return "TEXT", false
+	}
+	if m[5] != "" {
+		// Arrays have no SQLite counterpart
+		return "TEXT", false
+	}
+	switch m[1] {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint",
+		"serial", "smallserial", "bigserial", "bool", "boolean", "bit", "year":
+		return "INTEGER", true
+	case "float", "double", "double precision", "real":
+		return "REAL", true
+	case "decimal", "numeric", "dec", "fixed":
+		return "NUMERIC", true
+	case "char", "varchar", "character", "character varying", "nchar", "nvarchar",
+		"tinytext", "text", "mediumtext", "longtext", "json", "jsonb", "uuid",
+		"date", "datetime", "timestamp", "time", "interval", "enum", "set":
+		return "TEXT", true
+	case "tinyblob", "blob", "mediumblob", "longblob", "binary", "varbinary", "bytea":
+		return "BLOB", true
+	}
+	return "TEXT", false
+}
+
+func isEnumType(columnType string) bool {
+	lower := strings.ToLower(columnType)
+	return strings.HasPrefix(lower, "enum(") || strings.HasPrefix(lower, "set(")
+}
+
+// sqliteDefault translates the column default, false if it can't be translated
+func sqliteDefault(col column) (string, bool) {
+	if !col.Default.Valid {
+		return "", true
+	}
+	value := strings.TrimSpace(col.Default.String)
+	if !col.DefaultExpr {
+		return "'" + strings.Replace(value, "'", "''", -1) + "'", true
+	}
+	switch {
+	case sqliteTimestampRe.MatchString(value):
+		return "CURRENT_TIMESTAMP", true
+	case postgresSequenceRe.MatchString(value):
+		// Integer primary key is autoincremented by SQLite
+		return "", true
+	case sqliteNumberRe.MatchString(value):
+		return value, true
+	case strings.EqualFold(value, "true"):
+		return "1", true
+	case strings.EqualFold(value, "false"):
+		return "0", true
+	}
+	if m := sqliteCastLiteralRe.FindStringSubmatch(value); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+func sqliteQuote(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
@@ -0,0 +1,108 @@
+package mysqldump
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vicdeo/go-obfuscate/config"
+)
+
+func TestSQLiteAffinity(t *testing.T) {
+	examples := map[string]string{
+		"int(11) unsigned":            "INTEGER",
+		"tinyint(1)":                  "INTEGER",
+		"double precision":            "REAL",
+		"decimal(10,2)":               "NUMERIC",
+		"character varying(255)":      "TEXT",
+		"timestamp without time zone": "TEXT",
+		"enum('a','b')":               "TEXT",
+		"longblob":                    "BLOB",
+		"bytea":                       "BLOB",
+	}
+	for columnType, expected := range examples {
+		affinity, known := sqliteAffinity(columnType)
+		assert.True(t, known, columnType)
+		assert.Equal(t, expected, affinity, columnType)
+	}
+
+	for _, columnType := range []string{"geometry", "integer[]"} {
+		affinity, known := sqliteAffinity(columnType)
+		assert.False(t, known, columnType)
+		assert.Equal(t, "TEXT", affinity, columnType)
+	}
+}
+
+func TestWriteSQLiteTable(t *testing.T) {
+	data, mock, err := getMockData()
+	assert.NoError(t, err, "an error was not expected when opening a stub database connection")
+	defer func() {
+		data.Close()
+		shouldDumpData = config.ShouldDumpData
+	}()
+	shouldDumpData = func(tableName string) bool {
+		return true
+	}
+
+	dir, err := ioutil.TempDir("", "sqlite")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	data.File = filepath.Join(dir, "test.sqlite")
+	assert.NoError(t, data.openSQLite())
+
+	cols := sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+		AddRow("id", "int(11)", "NO", "PRI", nil, "auto_increment").
+		AddRow("email", "varchar(255)", "YES", "UNI", nil, "").
+		AddRow("state", "enum('new','old')", "NO", "", "new", "").
+		AddRow("area", "polygon", "YES", "", nil, "")
+
+	rows := sqlmock.NewRowsWithColumnDefinition(c("id", 0), c("email", ""), c("state", ""), c("area", "")).
+		AddRow(1, "test@test.de", "new", nil).
+		AddRow(2, nil, "old", nil)
+
+	createTableRows := sqlmock.NewRows([]string{"Table", "Create Table"}).
+		AddRow("test", "CREATE TABLE `test` (\n  `id` int(11) NOT NULL AUTO_INCREMENT,\n  PRIMARY KEY (`id`),\n  CONSTRAINT `fk` FOREIGN KEY (`id`) REFERENCES `other` (`id`)\n) ENGINE=InnoDB")
+
+	mock.ExpectQuery("^SHOW CREATE TABLE `test`$").WillReturnRows(createTableRows)
+	mock.ExpectQuery("^SHOW COLUMNS FROM `test`$").WillReturnRows(cols)
+	mockMariaDBDefaults(mock, "test")
+	mockMaxLengths(mock, "test")
+	mock.ExpectQuery("^SELECT (.+) FROM `test`$").WillReturnRows(rows)
+
+	assert.NoError(t, data.writeSQLiteTable(data.createTable("test")))
+	assert.NoError(t, data.closeSQLite())
+
+	// we make sure that all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet(), "there were unfulfilled expections")
+
+	assert.Equal(t, []string{
+		"test.state: allowed values of enum('new','old') are not enforced",
+		"test.area: type polygon is stored as TEXT",
+		"test: constraint is not translated: CONSTRAINT `fk` FOREIGN KEY (`id`) REFERENCES `other` (`id`)",
+	}, data.Warnings)
+
+	db, err := sql.Open("sqlite", data.File)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var email sql.NullString
+	var count int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*), MAX(email) FROM "test"`).Scan(&count, &email))
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "test@test.de", email.String)
+
+	var createSQL string
+	assert.NoError(t, db.QueryRow(`SELECT sql FROM sqlite_master WHERE name = 'test'`).Scan(&createSQL))
+	assert.Equal(t, `CREATE TABLE "test" (
+  "id" INTEGER NOT NULL,
+  "email" TEXT UNIQUE,
+  "state" TEXT NOT NULL DEFAULT 'new',
+  "area" TEXT,
+  PRIMARY KEY ("id")
+)`, createSQL)
+}
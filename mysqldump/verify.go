@@ -0,0 +1,222 @@
+package mysqldump
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vicdeo/go-obfuscate/config"
+)
+
+// DumpStats summarizes a verified dump
+type DumpStats struct {
+	Format string
+	Tables int
+	// Count is the number of statements or rows, as named by CountOf
+	Count   int
+	CountOf string
+}
+
+const (
+	dumpCompletedMarker  = "-- Dump completed on"
+	tableStructureMarker = "-- Table structure for table"
+)
+
+// Verify checks that a dump written by Dump was not interrupted
+func Verify(path string) (*DumpStats, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case info.IsDir():
+		return verifyDirectory(path)
+	case strings.HasSuffix(path, "."+config.FormatSQLite):
+		return verifySQLite(path)
+	}
+	return verifySQL(path)
+}
+
+// verifySQL expects the footer to be the last line of the dump
+func verifySQL(path string) (*DumpStats, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stats := &DumpStats{Format: config.FormatSQL, CountOf: "INSERT statements"}
+	var last string
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadString('\n')
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			last = trimmed
+			switch {
+			case strings.HasPrefix(trimmed, tableStructureMarker):
+				stats.Tables++
+			case strings.HasPrefix(trimmed, "INSERT INTO "):
+				stats.Count++
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !strings.HasPrefix(last, dumpCompletedMarker) {
+		return nil, errors.New("the dump completion footer is missing")
+	}
+	return stats, nil
+}
+
+func verifySQLite(path string) (*DumpStats, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow("PRAGMA integrity_check").Scan(&result); err != nil {
+		return nil, err
+	}
+	if result != "ok" {
+		return nil, fmt.Errorf("integrity check failed: %s", result)
+	}
+
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := &DumpStats{Format: config.FormatSQLite, Tables: len(tables), CountOf: "rows"}
+	for _, name := range tables {
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + sqliteQuote(name)).Scan(&count); err != nil {
+			return nil, err
+		}
+		stats.Count += count
+	}
+	return stats, nil
+}
+
+// verifyDirectory checks that every schema sidecar has its data file and every data file has its sidecar
+// The sidecar is written after the data, so a missing one means an interrupted table
+func verifyDirectory(path string) (*DumpStats, error) {
+	schemas, err := filepath.Glob(filepath.Join(path, "*.schema.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(schemas) == 0 {
+		return nil, errors.New("no table schema found")
+	}
+
+	stats := &DumpStats{CountOf: "rows"}
+	described := make(map[string]bool, len(schemas))
+	for _, schemaFile := range schemas {
+		b, err := ioutil.ReadFile(schemaFile)
+		if err != nil {
+			return nil, err
+		}
+		var schema tableSchema
+		if err := json.Unmarshal(b, &schema); err != nil {
+			return nil, fmt.Errorf("%s: %v", schemaFile, err)
+		}
+		if stats.Format != "" && stats.Format != schema.Format {
+			return nil, fmt.Errorf("%s: format %s differs from %s", schemaFile, schema.Format, stats.Format)
+		}
+		stats.Format = schema.Format
+
+		count, err := countRows(filepath.Join(path, schema.File), schema.Format)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %v", schema.Table, err)
+		}
+		described[schema.File] = true
+		stats.Tables++
+		stats.Count += count
+	}
+
+	for _, format := range []string{config.FormatCSV, config.FormatNDJSON} {
+		files, err := filepath.Glob(filepath.Join(path, "*."+format))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if !described[filepath.Base(file)] {
+				return nil, fmt.Errorf("%s: the schema file is missing, the table dump was interrupted", file)
+			}
+		}
+	}
+	return stats, nil
+}
+
+func countRows(path, format string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	count := 0
+	switch format {
+	case config.FormatCSV:
+		r := csv.NewReader(f)
+		for {
+			_, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return 0, err
+			}
+			count++
+		}
+		// The header is not a row
+		if count == 0 {
+			return 0, errors.New("the header line is missing")
+		}
+		return count - 1, nil
+	case config.FormatNDJSON:
+		r := bufio.NewReader(f)
+		for {
+			line, err := r.ReadBytes('\n')
+			if len(line) > 0 {
+				if !json.Valid(line) {
+					return 0, fmt.Errorf("line %d is not a valid JSON", count+1)
+				}
+				count++
+			}
+			if err == io.EOF {
+				return count, nil
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown output format %q", format)
+}
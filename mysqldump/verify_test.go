@@ -0,0 +1,72 @@
+package mysqldump
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySQL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.sql")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(restoreDump), 0666))
+
+	stats, err := Verify(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &DumpStats{Format: "sql", Tables: 1, Count: 1, CountOf: "INSERT statements"}, stats)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(restoreDump[:strings.Index(restoreDump, "-- Dump completed")]), 0666))
+	_, err = Verify(path)
+	assert.EqualError(t, err, "the dump completion footer is missing")
+}
+
+func TestVerifySQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.sqlite")
+	db, err := sql.Open("sqlite", path)
+	assert.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE "users" ("id" INTEGER); INSERT INTO "users" VALUES (1), (2); CREATE TABLE "empty" ("id" INTEGER)`)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+
+	stats, err := Verify(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &DumpStats{Format: "sqlite", Tables: 2, Count: 2, CountOf: "rows"}, stats)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("not a database"), 0666))
+	_, err = Verify(path)
+	assert.Error(t, err)
+}
+
+func TestVerifyDirectory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0666))
+	}
+
+	_, err := Verify(dir)
+	assert.EqualError(t, err, "no table schema found")
+
+	write("users.csv", "id,email\n1,a@test.de\n2,\n")
+	write("users.schema.json", `{"table": "users", "format": "csv", "file": "users.csv"}`)
+	stats, err := Verify(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, &DumpStats{Format: "csv", Tables: 1, Count: 2, CountOf: "rows"}, stats)
+
+	// The dump was interrupted before the schema of orders was written
+	write("orders.csv", "id\n1\n")
+	_, err = Verify(dir)
+	assert.EqualError(t, err, filepath.Join(dir, "orders.csv")+": the schema file is missing, the table dump was interrupted")
+
+	write("orders.schema.json", `{"table": "orders", "format": "csv", "file": "orders.csv"}`)
+	stats, err = Verify(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, &DumpStats{Format: "csv", Tables: 2, Count: 3, CountOf: "rows"}, stats)
+
+	// The schema is written after the data file only
+	write("orders.schema.json", `{"table": "orders", "format": "csv", "file": "missing.csv"}`)
+	_, err = Verify(dir)
+	assert.Error(t, err)
+}